@@ -0,0 +1,96 @@
+package punkt
+
+import (
+  "fmt"
+  "sort"
+)
+
+// A curated list of abbreviations that are common in one domain but rare in
+// the newswire text the bundled models were trained on. Entries are stored
+// like LanguageParameters.AbbrevTypes: lower case without the final period.
+type AbbrevPack struct {
+  Domain string
+  Language string
+  AbbrevTypes []string
+}
+
+var abbrevPacks = []AbbrevPack{
+  {"legal", "english", []string{
+    "amend", "cert", "ch", "cir", "cl", "const", "id", "para", "paras", "pp",
+    "rev", "sec", "seq", "stat", "subsec", "supp", "u.s.c", "v", "vs",
+  }},
+  {"biomedical", "english", []string{
+    "al", "approx", "b.i.d", "cf", "conc", "e.g", "fig", "figs", "hr", "hrs",
+    "i.e", "i.m", "i.v", "max", "min", "p.o", "pt", "pts", "q.d", "q.i.d",
+    "resp", "s.c", "sp", "spp", "t.i.d", "vs", "wk",
+  }},
+  {"financial", "english", []string{
+    "approx", "avg", "bn", "bros", "co", "corp", "dept", "est", "excl", "inc",
+    "incl", "llc", "ltd", "mln", "mn", "mo", "mos", "n.a", "p.a", "plc",
+    "q.o.q", "qtr", "y.o.y", "yr", "yrs",
+  }},
+  {"academic", "english", []string{
+    "al", "approx", "assoc", "ca", "cf", "chap", "cit", "dept", "e.g", "ed",
+    "eds", "eq", "eqs", "esp", "fig", "figs", "i.e", "ibid", "loc", "n.d",
+    "op", "pp", "prof", "ref", "refs", "repr", "resp", "trans", "univ", "viz",
+    "vol", "vols",
+  }},
+  {"legal", "german", []string{
+    "a.a.o", "abs", "art", "aufl", "bgbl", "f", "ff", "ggf", "i.s.d", "i.s.v",
+    "i.v.m", "lit", "m.w.n", "nr", "rdnr", "rn", "s", "vgl", "ziff",
+  }},
+  {"financial", "german", []string{
+    "abzgl", "bzw", "ca", "exkl", "ggü", "inkl", "mio", "mrd", "mwst", "tsd",
+    "zzgl",
+  }},
+  {"academic", "german", []string{
+    "a.a.o", "aufl", "bd", "bspw", "ca", "d.h", "dipl", "ebd", "evtl", "f",
+    "ff", "hrsg", "i.d.r", "ing", "jh", "jhd", "s", "sog", "u.a", "vgl", "z.b",
+  }},
+  {"legal", "french", []string{
+    "al", "art", "cass", "cf", "civ", "crim", "décr", "ibid", "ord", "p", "pp",
+  }},
+  {"academic", "french", []string{
+    "apr", "av", "cf", "chap", "cit", "env", "ex", "fig", "ibid", "op", "p",
+    "pp", "vol", "éd",
+  }},
+  {"legal", "spanish", []string{
+    "art", "arts", "cf", "núm", "pág", "págs",
+  }},
+  {"academic", "spanish", []string{
+    "aprox", "cap", "cf", "ed", "fig", "núm", "p.ej", "pág", "págs", "vol",
+  }},
+}
+
+// All packs available for a language, sorted by domain
+func AbbrevPacks(language string) (out []AbbrevPack) {
+  for _, p := range abbrevPacks {
+    if p.Language == language {
+      out = append(out, p)
+    }
+  }
+
+  sort.Slice(out, func(i, j int) bool { return out[i].Domain < out[j].Domain })
+  return
+}
+
+func FindAbbrevPack(domain, language string) (AbbrevPack, bool) {
+  for _, p := range abbrevPacks {
+    if p.Domain == domain && p.Language == language {
+      return p, true
+    }
+  }
+
+  return AbbrevPack{}, false
+}
+
+// Adds the abbreviations of the pack to an overlay
+func (p AbbrevPack) Apply(o *Overlay) {
+  for _, a := range p.AbbrevTypes {
+    o.SaveAbbrevType(a)
+  }
+}
+
+func (p AbbrevPack) String() string {
+  return fmt.Sprintf("%s/%s (%d abbreviations)", p.Language, p.Domain, len(p.AbbrevTypes))
+}
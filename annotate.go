@@ -17,6 +17,11 @@ var ORTHO_MAP = map[string]OrthoContext {
     "unknown|lower": ORTHO_UNK_LC,
 }
 
+// The flag for a token seen in the given context: initial, internal or unknown
+func orthoFlag(context string, tok *Token) OrthoContext {
+  return ORTHO_MAP[context + "|" + tok.FirstCase()]
+}
+
 type OrthoHeuristicResult byte
 
 const (
@@ -28,7 +33,7 @@ const (
 // the orthographic heuristic, which decides for a token following an abbreviation or an ellipsis on the basis 
 // of the orthographic statistics gathered for all word types whether it represents good evidence for a preceding
 // sentence boundary or not.
-func GuessOrthographicBoundary(parameters *LanguageParameters, token *Token) OrthoHeuristicResult {
+func GuessOrthographicBoundary(parameters ParameterSet, token *Token) OrthoHeuristicResult {
   punctRegexp := regexp.MustCompile("[;,:.!?]")
 
   if punctRegexp.MatchString(token.Value) {
@@ -46,7 +51,7 @@ func GuessOrthographicBoundary(parameters *LanguageParameters, token *Token) Ort
   }
 }
 
-func AnnotateFirstPass(parameters *LanguageParameters, tokens []*Token) []*Token {
+func AnnotateFirstPass(parameters ParameterSet, tokens []*Token) []*Token {
   for i := range tokens {
     str := tokens[i].Value
 
@@ -72,7 +77,7 @@ func AnnotateFirstPass(parameters *LanguageParameters, tokens []*Token) []*Token
   return tokens
 }
 
-func AnnotateSecondPass(parameters *LanguageParameters, tokens []*Token) []*Token {
+func AnnotateSecondPass(parameters ParameterSet, tokens []*Token) []*Token {
   for i := range tokens {
     if i == 0 {
       continue
@@ -134,7 +139,7 @@ func AnnotateSecondPass(parameters *LanguageParameters, tokens []*Token) []*Toke
   return tokens
 }
 
-func AnnotateTokens(parameters *LanguageParameters, tokens []*Token) []*Token {
+func AnnotateTokens(parameters ParameterSet, tokens []*Token) []*Token {
   tokens = AnnotateFirstPass(parameters, tokens)
   tokens = AnnotateSecondPass(parameters, tokens)
   return tokens
@@ -0,0 +1,87 @@
+// Command punktgen compiles a Punkt model file into a Go source file, so the
+// model can be built into a binary without loading or parsing it at run time.
+//
+//   //go:generate go run github.com/harrisj/punkt/cmd/punktgen -pkg english -o english/model.go ../data/english.json
+//
+// The generated package exports Tables (the model as sorted tables) and
+// Parameters(), which returns a ready to use *punkt.LanguageParameters.
+//
+// With -mapped it writes the model in the memory mapped format instead, to
+// be opened with punkt.OpenMappedParameters:
+//
+//   punktgen -mapped -o english.punkt data/english.json
+package main
+
+import (
+  "bytes"
+  "flag"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+
+  "github.com/harrisj/punkt"
+)
+
+func main() {
+  pkg := flag.String("pkg", "", "package name of the generated file (default: model file name)")
+  out := flag.String("o", "", "output file (default: standard output)")
+  mapped := flag.Bool("mapped", false, "write the memory mapped format instead of Go source")
+
+  flag.Usage = func() {
+    fmt.Fprintf(os.Stderr, "usage: punktgen [-pkg name] [-o file.go] model.json\n")
+    fmt.Fprintf(os.Stderr, "       punktgen -mapped [-o file.punkt] model.json\n")
+    flag.PrintDefaults()
+  }
+
+  flag.Parse()
+
+  if flag.NArg() != 1 {
+    flag.Usage()
+    os.Exit(2)
+  }
+
+  path := flag.Arg(0)
+
+  if *pkg == "" {
+    base := filepath.Base(path)
+    *pkg = base[:len(base)-len(filepath.Ext(base))]
+  }
+
+  params, err := punkt.LoadParametersFromFile(path)
+  if err != nil {
+    fail(err)
+  }
+
+  var src []byte
+
+  if *mapped {
+    var b bytes.Buffer
+    err = punkt.WriteMappedParameters(&b, params)
+    src = b.Bytes()
+  } else {
+    src, err = punkt.GenerateGoSource(params, *pkg, filepath.ToSlash(path))
+  }
+
+  if err != nil {
+    fail(err)
+  }
+
+  if *out == "" {
+    os.Stdout.Write(src)
+    return
+  }
+
+  if err := os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+    fail(err)
+  }
+
+  if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+    fail(err)
+  }
+}
+
+func fail(err error) {
+  fmt.Fprintf(os.Stderr, "punktgen: %v\n", err)
+  os.Exit(1)
+}
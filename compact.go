@@ -0,0 +1,424 @@
+package punkt
+
+import (
+  "fmt"
+  "hash/maphash"
+  "math/bits"
+  "sort"
+  "strings"
+)
+
+// Orthographic context only uses bits 1 to 6, so set membership goes in the
+// remaining bits of the same byte.
+const (
+  compactAbbrev uint8 = 1
+  compactStarter uint8 = 1 << 7
+)
+
+// A read-only, compact form of LanguageParameters for serving. Every type
+// from every set is stored once in a single string, laid out in the order of
+// a perfect hash so a lookup goes straight to its entry. Each type follows a
+// byte with its set membership and orthographic flags, so a lookup reads the
+// offsets of its entry and then one run of bytes. Collocations are a small
+// hash set of pairs of entry indexes. It answers the same lookups as
+// LanguageParameters through the ParameterSet interface, in a fraction of
+// the memory and a little faster.
+type CompactParameters struct {
+  hashSeed uint64
+  data string
+  offsets []uint32
+  seeds []uint32
+  collocations []uint64 // open addressing, compactNoCollocation when empty
+  Metadata ModelMetadata
+}
+
+const compactNoCollocation = ^uint64(0)
+
+// Builds the compact form of the parameters. Only the known orthographic
+// context flags are kept.
+func (p LanguageParameters) Compact() *CompactParameters {
+  return p.Tables().Compact()
+}
+
+func (t ParameterTables) Compact() *CompactParameters {
+  // two types can only have the same hash with one seed, so try another
+  seed := newHashSeed()
+  l, err := buildCompactLayout(t, compactHashing(seed))
+  for err != nil {
+    seed = newHashSeed()
+    l, err = buildCompactLayout(t, compactHashing(seed))
+  }
+
+  c := &CompactParameters{
+    hashSeed: seed,
+    offsets: make([]uint32, len(l.keys)+1),
+    seeds: l.seeds,
+    Metadata: t.Metadata,
+  }
+
+  var b strings.Builder
+  for i, v := range l.keys {
+    b.WriteByte(l.info[i])
+    b.WriteString(v)
+    c.offsets[i+1] = uint32(b.Len())
+  }
+  c.data = b.String()
+
+  if len(l.collocations) > 0 {
+    c.collocations = make([]uint64, nextPowerOfTwo(2*len(l.collocations)))
+    for j := range c.collocations {
+      c.collocations[j] = compactNoCollocation
+    }
+
+    for _, k := range l.collocations {
+      j := c.collocationSlot(k)
+      for c.collocations[j] != compactNoCollocation {
+        j = (j + 1) & (len(c.collocations) - 1)
+      }
+      c.collocations[j] = k
+    }
+  }
+
+  return c
+}
+
+// The entries of a compact model, in slot order. Shared with the memory
+// mapped format, which hashes the same in every process.
+type compactLayout struct {
+  seeds []uint32
+  keys []string
+  info []uint8
+  collocations []uint64
+}
+
+func buildCompactLayout(t ParameterTables, hashing perfectHashing) (l compactLayout, err error) {
+  unique := map[string]bool{}
+  for _, v := range t.AbbrevTypes {
+    unique[v] = true
+  }
+  for _, v := range t.SentenceStarters {
+    unique[v] = true
+  }
+  for _, v := range t.OrthoTypes {
+    unique[v] = true
+  }
+  for _, v := range t.Collocations {
+    unique[v[0]] = true
+    unique[v[1]] = true
+  }
+
+  types := sortedKeys(unique)
+  hashes := make([]uint64, len(types))
+  typeOf := make(map[uint64]string, len(types))
+
+  for i, v := range types {
+    hashes[i] = hashing.hash(v)
+
+    // no seed can tell these apart
+    if other, found := typeOf[hashes[i]]; found {
+      return l, fmt.Errorf("punkt: types %q and %q have the same hash", other, v)
+    }
+    typeOf[hashes[i]] = v
+  }
+
+  seeds, slots, slotCount, err := buildPerfectHash(hashes, hashing)
+  if err != nil {
+    return l, err
+  }
+
+  l.seeds = seeds
+  l.keys = make([]string, slotCount)
+  l.info = make([]uint8, slotCount)
+  slotOf := make(map[string]uint32, len(types))
+
+  for i, v := range types {
+    l.keys[slots[i]] = v
+    slotOf[v] = slots[i]
+  }
+
+  for _, v := range t.AbbrevTypes {
+    l.info[slotOf[v]] |= compactAbbrev
+  }
+
+  for _, v := range t.SentenceStarters {
+    l.info[slotOf[v]] |= compactStarter
+  }
+
+  for j, v := range t.OrthoTypes {
+    l.info[slotOf[v]] |= uint8(t.OrthoFlags[j] & orthoKnownFlags)
+  }
+
+  for _, v := range t.Collocations {
+    l.collocations = append(l.collocations, uint64(slotOf[v[0]])<<32 | uint64(slotOf[v[1]]))
+  }
+  sort.Slice(l.collocations, func(i, j int) bool { return l.collocations[i] < l.collocations[j] })
+
+  return
+}
+
+func (c *CompactParameters) key(i int) string {
+  return c.data[c.offsets[i]+1 : c.offsets[i+1]]
+}
+
+func (c *CompactParameters) info(i int) uint8 {
+  return c.data[c.offsets[i]]
+}
+
+// Unused slots hold an empty key with no flags, so they never answer true.
+// The length of a key is checked first, so most misses don't read the data.
+func (c *CompactParameters) lookup(s string) (int, bool) {
+  if len(c.seeds) == 0 {
+    return 0, false
+  }
+
+  h := hashString(c.hashSeed, s)
+  seed := c.seeds[compactBucket(h) & uint64(len(c.seeds) - 1)]
+  i := int(compactSlot(h, uint64(seed)) & uint64(len(c.offsets) - 2))
+  start, end := c.offsets[i]+1, c.offsets[i+1]
+
+  return i, int(end - start) == len(s) && c.data[start:end] == s
+}
+
+func (c *CompactParameters) HasAbbrevType(s string) bool {
+  i, found := c.lookup(s)
+  return found && c.info(i) & compactAbbrev != 0
+}
+
+func (c *CompactParameters) HasSentenceStarter(s string) bool {
+  i, found := c.lookup(s)
+  return found && c.info(i) & compactStarter != 0
+}
+
+func (c *CompactParameters) collocationSlot(k uint64) int {
+  return int((k * 0x9e3779b97f4a7c15) >> 32) & (len(c.collocations) - 1)
+}
+
+func (c *CompactParameters) HasCollocation(s1, s2 string) bool {
+  if len(c.collocations) == 0 {
+    return false
+  }
+
+  i1, found1 := c.lookup(s1)
+  i2, found2 := c.lookup(s2)
+  if !found1 || !found2 {
+    return false
+  }
+
+  k := uint64(i1)<<32 | uint64(i2)
+  for j := c.collocationSlot(k); c.collocations[j] != compactNoCollocation; j = (j + 1) & (len(c.collocations) - 1) {
+    if c.collocations[j] == k {
+      return true
+    }
+  }
+
+  return false
+}
+
+func (c *CompactParameters) GetOrthographicContext(s string) OrthoContext {
+  i, found := c.lookup(s)
+  if !found {
+    return 0
+  }
+
+  return OrthoContext(c.info(i)) & orthoKnownFlags
+}
+
+// Converts back to sorted tables, eg to build LanguageParameters again
+func (c *CompactParameters) Tables() (t ParameterTables) {
+  p := &LanguageParameters{Metadata: c.Metadata}
+
+  for i := 0; i < len(c.offsets) - 1; i++ {
+    k, info := c.key(i), c.info(i)
+
+    if info & compactAbbrev != 0 {
+      p.SaveAbbrevType(k)
+    }
+
+    if info & compactStarter != 0 {
+      p.SaveSentenceStarter(k)
+    }
+
+    if flags := OrthoContext(info) & orthoKnownFlags; flags != 0 {
+      p.SetOrthographicContext(k, flags)
+    }
+  }
+
+  for _, v := range c.collocations {
+    if v != compactNoCollocation {
+      p.SaveCollocation(c.key(int(v >> 32)), c.key(int(v & 0xffffffff)))
+    }
+  }
+
+  return p.Tables()
+}
+
+// the index is rebuilt in every process, so a random seed is fine
+func newHashSeed() uint64 {
+  return maphash.String(maphash.MakeSeed(), "")
+}
+
+// A wyhash style hash that reads the string a word at a time, as types are
+// short and this is most of the cost of a lookup
+func hashString(seed uint64, s string) uint64 {
+  n := len(s)
+  h := seed
+  var v uint64
+
+  switch {
+  case n > 8:
+    for i := 0; i < n-8; i += 8 {
+      h = foldedMultiply(load64(s[i:]) ^ 0xa0761d6478bd642f, h ^ 0xe7037ed1a0b428db)
+    }
+    v = load64(s[n-8:])
+  case n >= 4:
+    v = uint64(load32(s))<<32 | uint64(load32(s[n-4:]))
+  case n > 0:
+    v = uint64(s[0])<<16 | uint64(s[n>>1])<<8 | uint64(s[n-1])
+  }
+
+  return foldedMultiply(v ^ 0xa0761d6478bd642f, h ^ uint64(n) ^ 0xe7037ed1a0b428db)
+}
+
+func foldedMultiply(a, b uint64) uint64 {
+  hi, lo := bits.Mul64(a, b)
+  return hi ^ lo
+}
+
+func load64(s string) uint64 {
+  _ = s[7]
+  return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+    uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
+
+func load32(s string) uint32 {
+  _ = s[3]
+  return uint32(s[0]) | uint32(s[1])<<8 | uint32(s[2])<<16 | uint32(s[3])<<24
+}
+
+// How a perfect hash hashes its keys, picks the bucket of a hash, and the
+// slot of a hash given the seed of its bucket
+type perfectHashing struct {
+  hash func(string) uint64
+  bucket func(h uint64) uint64
+  slot func(h, seed uint64) uint64
+}
+
+// hashString is already well mixed, so the bucket is its high bits and a
+// slot takes a single multiply
+func compactHashing(seed uint64) perfectHashing {
+  return perfectHashing{func(s string) uint64 { return hashString(seed, s) }, compactBucket, compactSlot}
+}
+
+func compactBucket(h uint64) uint64 {
+  return h >> 32
+}
+
+// every bit of h counts, so keys with different hashes only share a slot
+// for some seeds
+func compactSlot(h, seed uint64) uint64 {
+  return foldedMultiply(h ^ seed * 0x9e3779b97f4a7c15, 0xbf58476d1ce4e5b9)
+}
+
+// splitmix64 finalizer, so every seed gives an independent looking hash
+func mixHash(h, seed uint64) uint64 {
+  z := h ^ (seed * 0x9e3779b97f4a7c15)
+  z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+  z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+  return z ^ (z >> 31)
+}
+
+func nextPowerOfTwo(n int) int {
+  p := 1
+  for p < n {
+    p <<= 1
+  }
+  return p
+}
+
+// Seeds tried for a bucket before the table is made bigger
+const perfectHashAttempts = 1 << 20
+
+// A hash and displace perfect hash over a fixed set of keys. Keys are spread
+// over buckets by one hash; each bucket then gets a seed for a second hash
+// that puts all of its keys in free slots. Looking up a key that is not in
+// the set still returns some slot, so callers must compare the key.
+//
+// Returns the seed for each bucket and the slot of each key, given the
+// distinct hash of each key. There are a few more slots than keys, and the
+// number of slots is a power of two. If a bucket can't be placed, the table
+// is doubled, up to eight slots a key.
+func buildPerfectHash(hashes []uint64, hashing perfectHashing) (seeds []uint32, slots []uint32, slotCount int, err error) {
+  seeds = make([]uint32, nextPowerOfTwo(len(hashes)/4 + 1))
+  slots = make([]uint32, len(hashes))
+
+  buckets := make([][]int, len(seeds))
+  seedMask := uint64(len(seeds) - 1)
+
+  for i, h := range hashes {
+    b := hashing.bucket(h) & seedMask
+    buckets[b] = append(buckets[b], i)
+  }
+
+  order := make([]int, len(buckets))
+  for i := range order {
+    order[i] = i
+  }
+
+  // place the biggest buckets first while the table is still empty
+  sort.SliceStable(order, func(i, j int) bool { return len(buckets[order[i]]) > len(buckets[order[j]]) })
+
+  for slotCount = nextPowerOfTwo(len(hashes) + len(hashes)/4 + 1); slotCount <= 8*nextPowerOfTwo(len(hashes)+1); slotCount *= 2 {
+    if placeBuckets(hashes, hashing, buckets, order, seeds, slots, slotCount) {
+      return seeds, slots, slotCount, nil
+    }
+  }
+
+  return nil, nil, 0, fmt.Errorf("punkt: can't build a perfect hash of %d types", len(hashes))
+}
+
+// Whether every bucket found a seed that puts its keys in free slots
+func placeBuckets(hashes []uint64, hashing perfectHashing, buckets [][]int, order []int, seeds, slots []uint32, slotCount int) bool {
+  used := make([]bool, slotCount)
+  slotMask := uint64(slotCount - 1)
+  placed := make([]uint64, 0, 16)
+
+  for _, b := range order {
+    if len(buckets[b]) == 0 {
+      break
+    }
+
+    found := false
+    for seed := uint32(1); seed <= perfectHashAttempts && !found; seed++ {
+      placed = placed[:0]
+      found = true
+
+      for _, i := range buckets[b] {
+        s := hashing.slot(hashes[i], uint64(seed)) & slotMask
+        if used[s] {
+          found = false
+          break
+        }
+
+        used[s] = true
+        placed = append(placed, s)
+      }
+
+      if found {
+        for j, i := range buckets[b] {
+          slots[i] = uint32(placed[j])
+        }
+        seeds[b] = seed
+      } else {
+        for _, s := range placed {
+          used[s] = false
+        }
+      }
+    }
+
+    if !found {
+      return false
+    }
+  }
+
+  return true
+}
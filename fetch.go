@@ -0,0 +1,198 @@
+package punkt
+
+import (
+  "context"
+  "crypto/sha256"
+  "encoding/hex"
+  "errors"
+  "fmt"
+  "io"
+  "io/ioutil"
+  "net/http"
+  "os"
+  "path/filepath"
+  "strings"
+  "time"
+)
+
+const (
+  // models bigger than this are refused unless ModelFetcher.MaxSize says otherwise
+  DEFAULT_MAX_MODEL_SIZE = 64 << 20
+
+  // used when ModelFetcher.Timeout is not set
+  DEFAULT_FETCH_TIMEOUT = 30 * time.Second
+)
+
+var (
+  ErrModelTooLarge = errors.New("punkt: model is larger than the maximum size")
+  ErrChecksumMismatch = errors.New("punkt: model does not match its SHA-256 checksum")
+  ErrBadChecksum = errors.New("punkt: checksum is not 64 hex digits")
+  ErrNotCached = errors.New("punkt: model is not cached and the fetcher is offline")
+)
+
+// Downloads model files over HTTP(S). Downloads are limited in time and size,
+// can be pinned to a SHA-256 checksum and are kept in CacheDir if it is set.
+// An Offline fetcher only reads from the cache.
+type ModelFetcher struct {
+  Client *http.Client
+  Timeout time.Duration
+  MaxSize int64
+  CacheDir string
+  Offline bool
+}
+
+var defaultFetcher = &ModelFetcher{}
+
+func (f *ModelFetcher) maxSize() int64 {
+  if f.MaxSize > 0 {
+    return f.MaxSize
+  }
+
+  return DEFAULT_MAX_MODEL_SIZE
+}
+
+func (f *ModelFetcher) client() *http.Client {
+  if f.Client != nil {
+    return f.Client
+  }
+
+  return http.DefaultClient
+}
+
+// Files are named after the pinned checksum, or after the URL if there is none
+func (f *ModelFetcher) cachePath(url, checksum string) string {
+  name := checksum
+  if name == "" {
+    sum := sha256.Sum256([]byte(url))
+    name = "url-" + hex.EncodeToString(sum[:])
+  }
+
+  return filepath.Join(f.CacheDir, name + ".json")
+}
+
+func verifyChecksum(contents []byte, checksum string) error {
+  if checksum == "" {
+    return nil
+  }
+
+  sum := sha256.Sum256(contents)
+  if hex.EncodeToString(sum[:]) != checksum {
+    return ErrChecksumMismatch
+  }
+
+  return nil
+}
+
+// Returns the model at url. If checksum (hex SHA-256) is not empty, the
+// contents must match it, whether they come from the cache or the network.
+func (f *ModelFetcher) Fetch(ctx context.Context, url, checksum string) ([]byte, error) {
+  checksum = strings.ToLower(checksum)
+
+  // the checksum names the cached file, so it must not be a path
+  if _, err := hex.DecodeString(checksum); checksum != "" && (len(checksum) != 2 * sha256.Size || err != nil) {
+    return nil, ErrBadChecksum
+  }
+
+  if f.CacheDir != "" {
+    contents, err := ioutil.ReadFile(f.cachePath(url, checksum))
+    if err == nil && verifyChecksum(contents, checksum) == nil {
+      return contents, nil
+    }
+  }
+
+  if f.Offline {
+    return nil, ErrNotCached
+  }
+
+  contents, err := f.download(ctx, url)
+  if err != nil {
+    return nil, err
+  }
+
+  if err := verifyChecksum(contents, checksum); err != nil {
+    return nil, err
+  }
+
+  if f.CacheDir != "" {
+    if err := f.store(f.cachePath(url, checksum), contents); err != nil {
+      return nil, err
+    }
+  }
+
+  return contents, nil
+}
+
+// Fetches the model and loads it with LoadParametersFromJSONStrict
+func (f *ModelFetcher) FetchParameters(ctx context.Context, url, checksum string) (*LanguageParameters, error) {
+  contents, err := f.Fetch(ctx, url, checksum)
+  if err != nil {
+    return nil, err
+  }
+
+  return LoadParametersFromJSONStrict(contents)
+}
+
+func (f *ModelFetcher) download(ctx context.Context, url string) ([]byte, error) {
+  timeout := f.Timeout
+  if timeout <= 0 {
+    timeout = DEFAULT_FETCH_TIMEOUT
+  }
+
+  ctx, cancel := context.WithTimeout(ctx, timeout)
+  defer cancel()
+
+  req, err := http.NewRequest("GET", url, nil)
+  if err != nil {
+    return nil, err
+  }
+
+  resp, err := f.client().Do(req.WithContext(ctx))
+  if err != nil {
+    return nil, err
+  }
+  defer resp.Body.Close()
+
+  if resp.StatusCode < 200 || resp.StatusCode > 299 {
+    return nil, fmt.Errorf("punkt: fetching %s: %s", url, resp.Status)
+  }
+
+  max := f.maxSize()
+  if resp.ContentLength > max {
+    return nil, ErrModelTooLarge
+  }
+
+  contents, err := ioutil.ReadAll(io.LimitReader(resp.Body, max + 1))
+  if err != nil {
+    return nil, err
+  }
+
+  if int64(len(contents)) > max {
+    return nil, ErrModelTooLarge
+  }
+
+  return contents, nil
+}
+
+// writes to a temporary file first so readers never see a partial model
+func (f *ModelFetcher) store(path string, contents []byte) error {
+  if err := os.MkdirAll(f.CacheDir, 0755); err != nil {
+    return err
+  }
+
+  tmp, err := ioutil.TempFile(f.CacheDir, ".fetch-")
+  if err != nil {
+    return err
+  }
+
+  _, err = tmp.Write(contents)
+  if closeErr := tmp.Close(); err == nil {
+    err = closeErr
+  }
+
+  if err != nil {
+    os.Remove(tmp.Name())
+    return err
+  }
+
+  return os.Rename(tmp.Name(), path)
+}
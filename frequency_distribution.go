@@ -1,50 +1,86 @@
 package punkt
 
 import (
+  "fmt"
   "sort"
 )
 
-type SampleCount struct {
-  Sample string
+type SampleCount[K comparable] struct {
+  Sample K
   Count int
 }
 
 // ByCount implements sort.Interface for []SampleCount based on
-// the Count field.
-type ByCount []SampleCount
+// the Count field, with ties in sample order.
+type ByCount[K comparable] []SampleCount[K]
+
+func (a ByCount[K]) Len() int           { return len(a) }
+func (a ByCount[K]) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a ByCount[K]) Less(i, j int) bool { // descending order
+  if a[i].Count != a[j].Count {
+    return a[i].Count > a[j].Count
+  }
+
+  return sampleLess(a[i].Sample, a[j].Sample)
+}
 
-func (a ByCount) Len() int           { return len(a) }
-func (a ByCount) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a ByCount) Less(i, j int) bool { return a[i].Count > a[j].Count } // descending order
+// Orders samples of the same count, so nothing depends on the order of the
+// map: strings and collocations by their types, anything else as printed
+func sampleLess[K comparable](a, b K) bool {
+  switch x := any(a).(type) {
+  case string:
+    return x < any(b).(string)
+  case Collocation:
+    return x.Less(any(b).(Collocation))
+  }
 
-type FrequencyDistribution struct {
+  return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// Counts samples of any comparable type, eg word types as strings or
+// collocations as Collocation pairs
+type FrequencyDistribution[K comparable] struct {
   N int
-  Counts map[string]int
+  Counts map[K]int
 
   // These are cached once calculated
-  MaxSample SampleCount
-  Sorted []SampleCount
+  MaxSample SampleCount[K]
+  Sorted []SampleCount[K]
 }
 
-func (f *FrequencyDistribution) Clear() {
+func (f *FrequencyDistribution[K]) Clear() {
   f.N = 0
   // FIXME: Is this cool?
-  f.Counts = make(map[string]int)
+  f.Counts = make(map[K]int)
   f.ClearCaches()
 }
 
-func (f *FrequencyDistribution) ClearCaches() {
-  f.MaxSample = SampleCount{"", 0}
-  f.Sorted = []SampleCount{}
+func (f *FrequencyDistribution[K]) ClearCaches() {
+  f.MaxSample = SampleCount[K]{}
+  f.Sorted = []SampleCount[K]{}
+}
+
+// A copy with its own counts, without the caches
+func (f *FrequencyDistribution[K]) Copy() FrequencyDistribution[K] {
+  c := FrequencyDistribution[K]{N: f.N}
+
+  if f.Counts != nil {
+    c.Counts = make(map[K]int, len(f.Counts))
+    for k, v := range f.Counts {
+      c.Counts[k] = v
+    }
+  }
+
+  return c
 }
 
-func (f *FrequencyDistribution) Get(sample string) int {
+func (f *FrequencyDistribution[K]) Get(sample K) int {
   return f.Counts[sample]
 }
 
-func (f *FrequencyDistribution) Set(sample string, value int) {
+func (f *FrequencyDistribution[K]) Set(sample K, value int) {
   if len(f.Counts) == 0 {
-    f.Counts = make(map[string]int)
+    f.Counts = make(map[K]int)
   }
 
   f.N += (value - f.Get(sample))
@@ -52,15 +88,15 @@ func (f *FrequencyDistribution) Set(sample string, value int) {
   f.ClearCaches()
 }
 
-func (f *FrequencyDistribution) Inc(sample string) {
+func (f *FrequencyDistribution[K]) Inc(sample K) {
   f.Set(sample, f.Get(sample)+1)
 }
 
-func (f *FrequencyDistribution) IncBy(sample string, n int) {
+func (f *FrequencyDistribution[K]) IncBy(sample K, n int) {
   f.Set(sample, f.Get(sample)+n)
 }
 
-func (f *FrequencyDistribution) FrequencyOf(sample string) float64 {
+func (f *FrequencyDistribution[K]) FrequencyOf(sample K) float64 {
   if f.N == 0 {
     return 0
   }
@@ -68,33 +104,35 @@ func (f *FrequencyDistribution) FrequencyOf(sample string) float64 {
   return float64(f.Get(sample))/float64(f.N)
 }
 
-func (f *FrequencyDistribution) Max() SampleCount {
-  if f.MaxSample.Sample == "" {
-    maxSample := ""
+func (f *FrequencyDistribution[K]) Max() SampleCount[K] {
+  var zero K
+
+  if f.MaxSample.Sample == zero {
+    maxSample := zero
     maxCount := -1
 
     for k, v := range f.Counts {
-      if v > maxCount {
+      if v > maxCount || (v == maxCount && sampleLess(k, maxSample)) {
         maxSample = k
         maxCount = v
       }
     }
 
-    f.MaxSample = SampleCount{maxSample,maxCount}
+    f.MaxSample = SampleCount[K]{maxSample,maxCount}
   }
 
   return f.MaxSample
 }
 
-func (f *FrequencyDistribution) OrderedSamples() []SampleCount {
+func (f *FrequencyDistribution[K]) OrderedSamples() []SampleCount[K] {
   if len(f.Sorted) == 0 {
-    f.Sorted = make([]SampleCount, 0, len(f.Counts))
+    f.Sorted = make([]SampleCount[K], 0, len(f.Counts))
 
     for k, v := range f.Counts {
-      f.Sorted = append(f.Sorted, SampleCount{k,v})
+      f.Sorted = append(f.Sorted, SampleCount[K]{k,v})
     }
 
-    sort.Sort(ByCount(f.Sorted))
+    sort.Sort(ByCount[K](f.Sorted))
   }
 
   return f.Sorted
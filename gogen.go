@@ -0,0 +1,105 @@
+package punkt
+
+import (
+  "bytes"
+  "fmt"
+  "go/format"
+  "strings"
+)
+
+// Writes a Go source file for package pkg that defines the model as sorted
+// ParameterTables, so programs can embed a model without loading or parsing
+// a file at run time. source is only used in the generated header comment.
+// This is what cmd/punktgen uses.
+func GenerateGoSource(p *LanguageParameters, pkg, source string) ([]byte, error) {
+  t := p.Tables()
+  var b bytes.Buffer
+
+  fmt.Fprintf(&b, "// Code generated by punktgen from %s; DO NOT EDIT.\n\n", source)
+  fmt.Fprintf(&b, "// Package %s holds a Punkt model compiled into Go.\n", pkg)
+  fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+  if t.Metadata.CreatedAt.IsZero() {
+    b.WriteString("import \"github.com/harrisj/punkt\"\n\n")
+  } else {
+    b.WriteString("import (\n\"time\"\n\n\"github.com/harrisj/punkt\"\n)\n\n")
+  }
+
+  b.WriteString("// Tables holds the model as sorted tables.\n")
+  b.WriteString("var Tables = punkt.ParameterTables{\n")
+
+  writeStringTable(&b, "AbbrevTypes", t.AbbrevTypes)
+
+  b.WriteString("Collocations: [][2]string{\n")
+  for _, c := range t.Collocations {
+    fmt.Fprintf(&b, "{%q, %q},\n", c[0], c[1])
+  }
+  b.WriteString("},\n")
+
+  writeStringTable(&b, "SentenceStarters", t.SentenceStarters)
+  writeStringTable(&b, "OrthoTypes", t.OrthoTypes)
+
+  b.WriteString("OrthoFlags: []punkt.OrthoContext{")
+  for i, f := range t.OrthoFlags {
+    if i % 16 == 0 {
+      b.WriteString("\n")
+    }
+    fmt.Fprintf(&b, "%d,", f)
+  }
+  b.WriteString("\n},\n")
+
+  writeMetadata(&b, t.Metadata)
+
+  b.WriteString("}\n\n")
+  b.WriteString("// Parameters returns a new copy of the model, ready for a punkt.Tokenizer.\n")
+  b.WriteString("func Parameters() *punkt.LanguageParameters {\nreturn Tables.Parameters()\n}\n")
+
+  out, err := format.Source(b.Bytes())
+  if err != nil {
+    return nil, fmt.Errorf("punkt: generated invalid Go source: %v", err)
+  }
+
+  return out, nil
+}
+
+func writeStringTable(b *bytes.Buffer, name string, values []string) {
+  fmt.Fprintf(b, "%s: []string{\n", name)
+  for _, v := range values {
+    fmt.Fprintf(b, "%q,\n", v)
+  }
+  b.WriteString("},\n")
+}
+
+func writeMetadata(b *bytes.Buffer, m ModelMetadata) {
+  if m.IsZero() {
+    return
+  }
+
+  fields := []string{}
+
+  if m.Language != "" {
+    fields = append(fields, fmt.Sprintf("Language: %q", m.Language))
+  }
+
+  if m.Corpus != "" {
+    fields = append(fields, fmt.Sprintf("Corpus: %q", m.Corpus))
+  }
+
+  if m.TokenCount != 0 {
+    fields = append(fields, fmt.Sprintf("TokenCount: %d", m.TokenCount))
+  }
+
+  if m.TrainerSettings != nil {
+    fields = append(fields, fmt.Sprintf("TrainerSettings: &%#v", *m.TrainerSettings))
+  }
+
+  if m.LibraryVersion != "" {
+    fields = append(fields, fmt.Sprintf("LibraryVersion: %q", m.LibraryVersion))
+  }
+
+  if !m.CreatedAt.IsZero() {
+    fields = append(fields, fmt.Sprintf("CreatedAt: time.Unix(%d, %d).UTC()", m.CreatedAt.Unix(), m.CreatedAt.Nanosecond()))
+  }
+
+  fmt.Fprintf(b, "Metadata: punkt.ModelMetadata{\n%s,\n},\n", strings.Join(fields, ",\n"))
+}
@@ -0,0 +1,181 @@
+package punkt
+
+import (
+  "fmt"
+  "sort"
+  "strings"
+  "sync"
+)
+
+// The models bundled in data/
+var LANGUAGES = []string{"czech", "danish", "dutch", "english", "estonian", "finnish", "french", "german", "greek", "italian", "norwegian", "polish", "portuguese", "slovene", "spanish", "swedish", "turkish"}
+
+// ISO 639-1 and 639-2 codes of the bundled languages. Norwegian Bokmål (nb)
+// and Nynorsk (nn) share the one Norwegian model.
+var defaultLanguageAliases = map[string]string{
+  "cs": "czech", "ces": "czech", "cze": "czech",
+  "da": "danish", "dan": "danish",
+  "nl": "dutch", "nld": "dutch", "dut": "dutch",
+  "en": "english", "eng": "english",
+  "et": "estonian", "est": "estonian",
+  "fi": "finnish", "fin": "finnish",
+  "fr": "french", "fra": "french", "fre": "french",
+  "de": "german", "deu": "german", "ger": "german",
+  "el": "greek", "ell": "greek", "gre": "greek",
+  "it": "italian", "ita": "italian",
+  "no": "norwegian", "nor": "norwegian", "nb": "norwegian", "nob": "norwegian", "nn": "norwegian", "nno": "norwegian",
+  "pl": "polish", "pol": "polish",
+  "pt": "portuguese", "por": "portuguese",
+  "sl": "slovene", "slv": "slovene",
+  "es": "spanish", "spa": "spanish",
+  "sv": "swedish", "swe": "swedish",
+  "tr": "turkish", "tur": "turkish",
+}
+
+var (
+  languageAliasesLock sync.RWMutex
+  languageAliases = map[string]string{}
+)
+
+// Returned when a language tag does not resolve to a bundled model or Variant
+type UnknownLanguageError struct {
+  Tag string
+  Closest []string
+}
+
+func (e *UnknownLanguageError) Error() string {
+  if len(e.Closest) == 0 {
+    return fmt.Sprintf("punkt: unknown language %q", e.Tag)
+  }
+
+  return fmt.Sprintf("punkt: unknown language %q, closest supported languages are %s", e.Tag, strings.Join(e.Closest, ", "))
+}
+
+// Tags are matched case insensitively, and _ is taken as - so "pt_BR" works
+func normalizeLanguageTag(tag string) string {
+  return strings.ToLower(strings.Replace(strings.TrimSpace(tag), "_", "-", -1))
+}
+
+func isBundledLanguage(name string) bool {
+  for _, l := range LANGUAGES {
+    if l == name {
+      return true
+    }
+  }
+
+  return false
+}
+
+// bundled or a registered Variant
+func isKnownLanguage(name string) bool {
+  if isBundledLanguage(name) {
+    return true
+  }
+
+  _, found := lookupVariant(name)
+  return found
+}
+
+// Makes tag resolve to the given bundled language or Variant, eg "de-AT" to
+// "german". Aliases are checked before the default codes, so they can also
+// override them. Registering an empty language removes the alias.
+func RegisterLanguageAlias(tag, language string) error {
+  tag = normalizeLanguageTag(tag)
+
+  if tag == "" {
+    return fmt.Errorf("punkt: empty language tag")
+  }
+
+  if language != "" && !isKnownLanguage(language) {
+    return &UnknownLanguageError{Tag: language, Closest: closestLanguages(language)}
+  }
+
+  languageAliasesLock.Lock()
+  defer languageAliasesLock.Unlock()
+
+  if language == "" {
+    delete(languageAliases, tag)
+  } else {
+    languageAliases[tag] = language
+  }
+
+  return nil
+}
+
+func lookupLanguage(tag string) (string, bool) {
+  languageAliasesLock.RLock()
+  name, found := languageAliases[tag]
+  languageAliasesLock.RUnlock()
+
+  if found {
+    return name, true
+  }
+
+  if name, found := defaultLanguageAliases[tag]; found {
+    return name, true
+  }
+
+  return tag, isKnownLanguage(tag)
+}
+
+// Returns the bundled language or Variant for a BCP 47 tag such as "en-US",
+// "pt-BR" or "nb", or a language name such as "English". Subtags are dropped
+// from the end until something matches, so "de-CH" falls back to "german"
+// unless an alias or a variant was registered for "de-CH" itself.
+func ResolveLanguage(tag string) (string, error) {
+  normalized := normalizeLanguageTag(tag)
+  subtags := strings.Split(normalized, "-")
+
+  for i := len(subtags); i > 0; i-- {
+    if name, found := lookupLanguage(strings.Join(subtags[:i], "-")); found {
+      return name, nil
+    }
+  }
+
+  return "", &UnknownLanguageError{Tag: tag, Closest: closestLanguages(subtags[0])}
+}
+
+// Up to three languages whose names or codes are closest to s
+func closestLanguages(s string) []string {
+  distances := map[string]int{}
+
+  consider := func(candidate, language string) {
+    d := editDistance(strings.ToLower(s), candidate)
+    if best, seen := distances[language]; !seen || d < best {
+      distances[language] = d
+    }
+  }
+
+  for _, l := range LANGUAGES {
+    consider(l, l)
+  }
+
+  for _, l := range variantNames() {
+    consider(l, l)
+  }
+
+  for code, l := range defaultLanguageAliases {
+    consider(code, l)
+  }
+
+  out := make([]string, 0, len(distances))
+  for l, d := range distances {
+    if d <= len(s)/2 + 1 {
+      out = append(out, l)
+    }
+  }
+
+  sort.Slice(out, func(i, j int) bool {
+    if distances[out[i]] != distances[out[j]] {
+      return distances[out[i]] < distances[out[j]]
+    }
+
+    return out[i] < out[j]
+  })
+
+  if len(out) > 3 {
+    out = out[:3]
+  }
+
+  return out
+}
@@ -0,0 +1,294 @@
+package punkt
+
+import (
+  "bufio"
+  "fmt"
+  "io"
+  "sort"
+  "strconv"
+  "strings"
+  "unicode"
+)
+
+type Severity int
+
+const (
+  SEVERITY_INFO Severity = iota // worth a look, often harmless
+  SEVERITY_WARNING              // probably a mistake in the model
+  SEVERITY_ERROR                // the model is invalid
+)
+
+func (s Severity) String() string {
+  switch s {
+  case SEVERITY_INFO:
+    return "info"
+  case SEVERITY_WARNING:
+    return "warning"
+  case SEVERITY_ERROR:
+    return "error"
+  default:
+    return fmt.Sprintf("severity(%d)", int(s))
+  }
+}
+
+const (
+  // abbreviations among this many most frequent reference words are flagged
+  DEFAULT_LINT_COMMON_WORD_RANK = 1000
+
+  // sentence starters outside this many most frequent reference words are flagged
+  DEFAULT_LINT_RARE_STARTER_RANK = 10000
+)
+
+type LintOptions struct {
+  // Word counts of a large corpus in the model's language, eg from
+  // LoadFrequencyList. The rules comparing with common words only run if
+  // it is set.
+  Reference *FrequencyDistribution[string]
+  CommonWordRank int
+  RareStarterRank int
+}
+
+// One problem found by Lint. Rule names the check, and Field uses the same
+// form as ValidationError, eg abbrev_types["gol"].
+type LintFinding struct {
+  Severity Severity
+  Rule string
+  Field string
+  Message string
+}
+
+func (f LintFinding) String() string {
+  return fmt.Sprintf("%s: %s: %s (%s)", f.Severity, f.Field, f.Message, f.Rule)
+}
+
+type LintReport []LintFinding
+
+// The highest severity found, or -1 if the report is empty. A release can
+// be gated on eg report.Max() < SEVERITY_WARNING.
+func (r LintReport) Max() Severity {
+  max := Severity(-1)
+
+  for _, f := range r {
+    if f.Severity > max {
+      max = f.Severity
+    }
+  }
+
+  return max
+}
+
+// The findings with at least the given severity
+func (r LintReport) AtLeast(s Severity) (out LintReport) {
+  for _, f := range r {
+    if f.Severity >= s {
+      out = append(out, f)
+    }
+  }
+
+  return
+}
+
+// One finding per line
+func (r LintReport) String() (out string) {
+  for _, f := range r {
+    out += f.String() + "\n"
+  }
+
+  return
+}
+
+// Looks for entries that are valid but suspicious, like abbreviations that
+// are common full words. Problems reported by Validate are included as
+// errors. Findings are sorted by severity, most severe first, then by field.
+func (p LanguageParameters) Lint(opts LintOptions) LintReport {
+  var report LintReport
+
+  add := func(severity Severity, rule, field, format string, args ...interface{}) {
+    report = append(report, LintFinding{severity, rule, field, fmt.Sprintf(format, args...)})
+  }
+
+  if err := p.Validate(); err != nil {
+    for _, e := range err.(ValidationErrors) {
+      add(SEVERITY_ERROR, "invalid", e.Field, "%s", e.Message)
+    }
+  }
+
+  lintTypes := func(name string, set map[string]bool) {
+    for _, k := range sortedKeys(set) {
+      field := fmt.Sprintf("%s[%q]", name, k)
+
+      if strings.IndexFunc(k, unicode.IsSpace) >= 0 {
+        add(SEVERITY_WARNING, "type_space", field, "type contains whitespace, but tokens never do")
+      }
+
+      if strings.IndexFunc(k, unicode.IsUpper) >= 0 {
+        add(SEVERITY_WARNING, "type_case", field, "type has upper case letters, but token types are lower case")
+      }
+    }
+  }
+
+  lintTypes("abbrev_types", p.AbbrevTypes)
+  lintTypes("sentence_starters", p.SentenceStarters)
+
+  for _, k := range sortedKeys(p.AbbrevTypes) {
+    if strings.HasSuffix(k, ".") && len(k) > 1 {
+      add(SEVERITY_WARNING, "abbrev_period", fmt.Sprintf("abbrev_types[%q]", k), "abbreviations are stored without their final period")
+    }
+  }
+
+  for _, k := range sortedCollocations(p.Collocations) {
+    field := fmt.Sprintf("collocations[%q]", k.String())
+    punct1, punct2 := isPunctuationType(k.Type1), isPunctuationType(k.Type2)
+
+    if punct1 && punct2 {
+      add(SEVERITY_WARNING, "punctuation_collocation", field, "both types are punctuation")
+    } else if punct1 || punct2 {
+      add(SEVERITY_INFO, "punctuation_collocation", field, "one type is punctuation")
+    }
+  }
+
+  orthoTypes := make(map[string]bool, len(p.OrthographicContext))
+  for k := range p.OrthographicContext {
+    orthoTypes[k] = true
+  }
+
+  for _, k := range sortedKeys(orthoTypes) {
+    flags := p.OrthographicContext[k]
+    field := fmt.Sprintf("ortho_context[%q]", k)
+
+    if flags & orthoKnownFlags != 0 && !hasCasedLetter(k) {
+      add(SEVERITY_WARNING, "ortho_no_case", field, "%v was seen for a type without letters that have case", flags)
+    }
+  }
+
+  for _, k := range sortedKeys(p.SentenceStarters) {
+    flags, found := p.OrthographicContext[k]
+
+    if found && flags & ORTHO_UC == 0 {
+      add(SEVERITY_WARNING, "starter_lower_case", fmt.Sprintf("sentence_starters[%q]", k), "frequent sentence starter was never seen upper case (%v)", flags)
+    }
+  }
+
+  if opts.Reference != nil {
+    ranks := referenceRanks(opts.Reference)
+
+    commonRank := opts.CommonWordRank
+    if commonRank <= 0 {
+      commonRank = DEFAULT_LINT_COMMON_WORD_RANK
+    }
+
+    rareRank := opts.RareStarterRank
+    if rareRank <= 0 {
+      rareRank = DEFAULT_LINT_RARE_STARTER_RANK
+    }
+
+    for _, k := range sortedKeys(p.AbbrevTypes) {
+      if rank, found := ranks[k]; found && rank <= commonRank {
+        add(SEVERITY_WARNING, "common_word_abbrev", fmt.Sprintf("abbrev_types[%q]", k), "abbreviation is the %s most common word in the reference", ordinal(rank))
+      }
+    }
+
+    for _, k := range sortedKeys(p.SentenceStarters) {
+      field := fmt.Sprintf("sentence_starters[%q]", k)
+
+      if rank, found := ranks[k]; !found {
+        add(SEVERITY_WARNING, "rare_starter", field, "sentence starter is not in the reference")
+      } else if rank > rareRank {
+        add(SEVERITY_WARNING, "rare_starter", field, "sentence starter is only the %s most common word in the reference", ordinal(rank))
+      }
+    }
+  }
+
+  sort.SliceStable(report, func(i, j int) bool {
+    if report[i].Severity != report[j].Severity {
+      return report[i].Severity > report[j].Severity
+    }
+
+    return report[i].Field < report[j].Field
+  })
+
+  return report
+}
+
+func isPunctuationType(s string) bool {
+  return strings.IndexFunc(s, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0
+}
+
+// numbers are all typed ##number##, so that does not count as having case
+func hasCasedLetter(s string) bool {
+  if s == "##number##" {
+    return false
+  }
+
+  return strings.IndexFunc(s, func(r rune) bool { return unicode.ToUpper(r) != unicode.ToLower(r) }) >= 0
+}
+
+// 1 for the most frequent words, with ties sharing a rank, so the result
+// does not depend on map order
+func referenceRanks(f *FrequencyDistribution[string]) map[string]int {
+  counts := make([]int, 0, len(f.Counts))
+  for _, v := range f.Counts {
+    counts = append(counts, v)
+  }
+  sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+
+  ranks := make(map[string]int, len(f.Counts))
+  for k, v := range f.Counts {
+    // the number of words with a higher count, plus one
+    ranks[k] = sort.Search(len(counts), func(i int) bool { return counts[i] <= v }) + 1
+  }
+
+  return ranks
+}
+
+func ordinal(n int) string {
+  suffix := "th"
+
+  switch {
+  case n % 100 >= 11 && n % 100 <= 13:
+  case n % 10 == 1:
+    suffix = "st"
+  case n % 10 == 2:
+    suffix = "nd"
+  case n % 10 == 3:
+    suffix = "rd"
+  }
+
+  return strconv.Itoa(n) + suffix
+}
+
+// Reads a word frequency list with one "word count" pair per line, as
+// published for many languages. Words are lower cased like token types, and
+// blank lines and lines starting with # are skipped.
+func LoadFrequencyList(r io.Reader) (*FrequencyDistribution[string], error) {
+  f := new(FrequencyDistribution[string])
+  scanner := bufio.NewScanner(r)
+  lineNo := 0
+
+  for scanner.Scan() {
+    lineNo++
+    line := strings.TrimSpace(scanner.Text())
+
+    if line == "" || strings.HasPrefix(line, "#") {
+      continue
+    }
+
+    fields := strings.Fields(line)
+    if len(fields) != 2 {
+      return nil, fmt.Errorf("punkt: frequency list line %d: expected a word and a count, got %q", lineNo, line)
+    }
+
+    count, err := strconv.Atoi(fields[1])
+    if err != nil || count < 0 {
+      return nil, fmt.Errorf("punkt: frequency list line %d: bad count %q", lineNo, fields[1])
+    }
+
+    f.IncBy(strings.ToLower(fields[0]), count)
+  }
+
+  if err := scanner.Err(); err != nil {
+    return nil, err
+  }
+
+  return f, nil
+}
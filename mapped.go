@@ -0,0 +1,320 @@
+package punkt
+
+import (
+  "bytes"
+  "encoding/binary"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "io"
+  "io/ioutil"
+  "os"
+  "sort"
+)
+
+// The memory mapped model format holds the same tables as CompactParameters
+// in one file that is queried in place, so opening a model costs almost
+// nothing and processes share its pages through the OS cache. All numbers
+// are little endian, and every section starts on an 8 byte boundary:
+//
+//   header       "PUNKTMAP", then uint32 format version, slot count, seed
+//                count, collocation count, key bytes and metadata bytes
+//   seeds        uint32 per hash bucket
+//   offsets      uint32 per slot, plus one, into the key bytes
+//   info         one byte per slot, with the flags of CompactParameters
+//   collocations sorted uint64 pairs of slots
+//   keys         the types, in slot order
+//   metadata     ModelMetadata as JSON
+//
+// Keys are hashed with FNV-1a, so the file means the same in every process.
+const (
+  MAPPED_MODEL_MAGIC = "PUNKTMAP"
+  MAPPED_MODEL_VERSION = 1
+  MAPPED_MODEL_EXT = ".punkt"
+
+  mappedHeaderSize = 32
+)
+
+var ErrBadMappedModel = errors.New("punkt: not a valid memory mapped model")
+
+// A model read straight from a memory mapped file (or a byte slice). It
+// answers the same lookups as LanguageParameters through ParameterSet.
+type MappedParameters struct {
+  buf []byte
+  unmap func() error
+
+  slotCount int
+  seedCount int
+  collocationCount int
+  seedsAt int
+  offsetsAt int
+  infoAt int
+  collocationsAt int
+  keysAt int
+  keysLen int
+
+  Metadata ModelMetadata
+}
+
+func align8(n int) int {
+  return (n + 7) &^ 7
+}
+
+// FNV-1a, which is the same in every process unlike the hash of CompactParameters
+func mappedHash(s string) uint64 {
+  h := uint64(14695981039346656037)
+  for i := 0; i < len(s); i++ {
+    h ^= uint64(s[i])
+    h *= 1099511628211
+  }
+
+  return h
+}
+
+// part of the file format, unlike the hashing of CompactParameters
+var mappedHashing = perfectHashing{mappedHash, mappedBucket, mixHash}
+
+func mappedBucket(h uint64) uint64 {
+  return mixHash(h, 0)
+}
+
+// Writes the parameters in the memory mapped format. The same parameters
+// always give the same bytes.
+func WriteMappedParameters(w io.Writer, p *LanguageParameters) error {
+  t := p.Tables()
+  l, err := buildCompactLayout(t, mappedHashing)
+  if err != nil {
+    return err
+  }
+
+  var metadata []byte
+  if !t.Metadata.IsZero() {
+    if metadata, err = json.Marshal(t.Metadata); err != nil {
+      return err
+    }
+  }
+
+  var keys bytes.Buffer
+  offsets := make([]uint32, len(l.keys)+1)
+  for i, k := range l.keys {
+    keys.WriteString(k)
+    offsets[i+1] = uint32(keys.Len())
+  }
+
+  var out bytes.Buffer
+  pad := func() {
+    for out.Len() % 8 != 0 {
+      out.WriteByte(0)
+    }
+  }
+  put := func(v interface{}) {
+    binary.Write(&out, binary.LittleEndian, v)
+  }
+
+  out.WriteString(MAPPED_MODEL_MAGIC)
+  put([]uint32{MAPPED_MODEL_VERSION, uint32(len(l.keys)), uint32(len(l.seeds)), uint32(len(l.collocations)), uint32(keys.Len()), uint32(len(metadata))})
+
+  put(l.seeds)
+  pad()
+  put(offsets)
+  pad()
+  out.Write(l.info)
+  pad()
+  put(l.collocations)
+  pad()
+  out.Write(keys.Bytes())
+  pad()
+  out.Write(metadata)
+
+  _, err = w.Write(out.Bytes())
+  return err
+}
+
+func SaveParametersToMapped(p *LanguageParameters, path string) error {
+  var b bytes.Buffer
+  if err := WriteMappedParameters(&b, p); err != nil {
+    return err
+  }
+
+  return ioutil.WriteFile(path, b.Bytes(), 0644)
+}
+
+// Maps the model file into memory. Call Close when the model is no longer
+// used; it must not be queried after that. On systems without mmap the file
+// is read into memory instead.
+func OpenMappedParameters(path string) (*MappedParameters, error) {
+  f, err := os.Open(path)
+  if err != nil {
+    return nil, err
+  }
+  defer f.Close()
+
+  buf, unmap, err := mapFile(f)
+  if err != nil {
+    return nil, err
+  }
+
+  m, err := NewMappedParameters(buf)
+  if err != nil {
+    unmap()
+    return nil, fmt.Errorf("%s: %v", path, err)
+  }
+
+  m.unmap = unmap
+  return m, nil
+}
+
+// Queries a model in the memory mapped format that is already in memory.
+// The buffer must not be changed while the model is used.
+func NewMappedParameters(buf []byte) (*MappedParameters, error) {
+  if len(buf) < mappedHeaderSize || string(buf[:8]) != MAPPED_MODEL_MAGIC {
+    return nil, ErrBadMappedModel
+  }
+
+  header := func(i int) int {
+    return int(binary.LittleEndian.Uint32(buf[8 + 4*i:]))
+  }
+
+  if header(0) != MAPPED_MODEL_VERSION {
+    return nil, fmt.Errorf("punkt: memory mapped model has format version %d, expected %d", header(0), MAPPED_MODEL_VERSION)
+  }
+
+  m := &MappedParameters{buf: buf, slotCount: header(1), seedCount: header(2), collocationCount: header(3), keysLen: header(4)}
+  metadataLen := header(5)
+
+  for _, n := range []int{m.slotCount, m.seedCount, m.collocationCount, m.keysLen, metadataLen} {
+    if n > len(buf) {
+      return nil, ErrBadMappedModel
+    }
+  }
+
+  // both are powers of two, as built by buildPerfectHash
+  if m.slotCount & (m.slotCount - 1) != 0 || m.seedCount & (m.seedCount - 1) != 0 || (m.slotCount == 0) != (m.seedCount == 0) {
+    return nil, ErrBadMappedModel
+  }
+
+  m.seedsAt = mappedHeaderSize
+  m.offsetsAt = align8(m.seedsAt + 4*m.seedCount)
+  m.infoAt = align8(m.offsetsAt + 4*(m.slotCount + 1))
+  m.collocationsAt = align8(m.infoAt + m.slotCount)
+  m.keysAt = align8(m.collocationsAt + 8*m.collocationCount)
+  metadataAt := align8(m.keysAt + m.keysLen)
+
+  if metadataAt + metadataLen != len(buf) {
+    return nil, ErrBadMappedModel
+  }
+
+  if metadataLen > 0 {
+    if err := json.Unmarshal(buf[metadataAt:], &m.Metadata); err != nil {
+      return nil, fmt.Errorf("punkt: memory mapped model metadata: %v", err)
+    }
+  }
+
+  return m, nil
+}
+
+// Unmaps the file. Models made with NewMappedParameters have nothing to release.
+func (m *MappedParameters) Close() error {
+  if m.unmap == nil {
+    return nil
+  }
+
+  err := m.unmap()
+  m.unmap = nil
+  m.buf = nil
+  return err
+}
+
+func (m *MappedParameters) u32(at int) uint32 {
+  return binary.LittleEndian.Uint32(m.buf[at:])
+}
+
+func (m *MappedParameters) collocation(i int) uint64 {
+  return binary.LittleEndian.Uint64(m.buf[m.collocationsAt + 8*i:])
+}
+
+// A damaged file gives wrong answers, never reads outside the mapping
+func (m *MappedParameters) key(i int) []byte {
+  start, end := int(m.u32(m.offsetsAt + 4*i)), int(m.u32(m.offsetsAt + 4*(i+1)))
+  if start > end || end > m.keysLen {
+    return nil
+  }
+
+  return m.buf[m.keysAt + start : m.keysAt + end]
+}
+
+func (m *MappedParameters) lookup(s string) (int, bool) {
+  if m.slotCount == 0 {
+    return 0, false
+  }
+
+  h := mappedHash(s)
+  seed := m.u32(m.seedsAt + 4*int(mappedBucket(h) & uint64(m.seedCount - 1)))
+  i := int(mixHash(h, uint64(seed)) & uint64(m.slotCount - 1))
+
+  return i, string(m.key(i)) == s
+}
+
+func (m *MappedParameters) HasAbbrevType(s string) bool {
+  i, found := m.lookup(s)
+  return found && m.buf[m.infoAt + i] & compactAbbrev != 0
+}
+
+func (m *MappedParameters) HasSentenceStarter(s string) bool {
+  i, found := m.lookup(s)
+  return found && m.buf[m.infoAt + i] & compactStarter != 0
+}
+
+func (m *MappedParameters) HasCollocation(s1, s2 string) bool {
+  i1, found1 := m.lookup(s1)
+  i2, found2 := m.lookup(s2)
+  if !found1 || !found2 {
+    return false
+  }
+
+  k := uint64(i1)<<32 | uint64(i2)
+  j := sort.Search(m.collocationCount, func(j int) bool { return m.collocation(j) >= k })
+  return j < m.collocationCount && m.collocation(j) == k
+}
+
+func (m *MappedParameters) GetOrthographicContext(s string) OrthoContext {
+  i, found := m.lookup(s)
+  if !found {
+    return 0
+  }
+
+  return OrthoContext(m.buf[m.infoAt + i]) & orthoKnownFlags
+}
+
+// Reads every entry back into sorted tables
+func (m *MappedParameters) Tables() ParameterTables {
+  p := &LanguageParameters{Metadata: m.Metadata}
+
+  for i := 0; i < m.slotCount; i++ {
+    k := string(m.key(i))
+    info := m.buf[m.infoAt + i]
+
+    if info & compactAbbrev != 0 {
+      p.SaveAbbrevType(k)
+    }
+
+    if info & compactStarter != 0 {
+      p.SaveSentenceStarter(k)
+    }
+
+    if flags := OrthoContext(info) & orthoKnownFlags; flags != 0 {
+      p.SetOrthographicContext(k, flags)
+    }
+  }
+
+  for j := 0; j < m.collocationCount; j++ {
+    v := m.collocation(j)
+    i1, i2 := int(v >> 32), int(v & 0xffffffff)
+
+    if i1 < m.slotCount && i2 < m.slotCount {
+      p.SaveCollocation(string(m.key(i1)), string(m.key(i2)))
+    }
+  }
+
+  return p.Tables()
+}
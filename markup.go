@@ -0,0 +1,290 @@
+package punkt
+
+import (
+  "bytes"
+  "context"
+  "encoding/xml"
+  "errors"
+  "fmt"
+  "io"
+  "io/ioutil"
+  "strings"
+  "unicode"
+)
+
+// Elements of HTML and TEI XML that start and end a paragraph
+var MARKUP_BLOCK_ELEMENTS = []string{
+  "address", "article", "blockquote", "body", "caption", "dd", "div", "dl", "dt",
+  "figcaption", "figure", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "li", "main",
+  "ol", "p", "pre", "section", "table", "td", "th", "tr", "ul",
+  "ab", "argument", "back", "cell", "closer", "epigraph", "front", "head", "item",
+  "lg", "list", "opener", "row", "sp", "speaker", "text", "trailer",
+}
+
+// Elements that start a line
+var MARKUP_LINE_ELEMENTS = []string{"br", "lb", "l"}
+
+// Elements whose content is not running text. "html/head" is a head inside
+// html and "/head" one at the top, where HTML without an html element puts
+// it, as TEI has headings called head.
+var MARKUP_SKIP_ELEMENTS = []string{
+  "button", "canvas", "footer", "form", "html/head", "/head", "header", "iframe", "math", "menu",
+  "nav", "noscript", "object", "option", "script", "select", "style", "svg",
+  "template", "textarea",
+  "fw", "note", "teiheader",
+}
+
+// Elements whose content is not parsed as markup by browsers, and so may
+// hold anything
+var markupRawTextElements = []string{"script", "style"}
+
+// Which elements of a document matter for its text. Names are matched
+// without their namespace and ignoring case, "parent/name" only matches
+// inside parent and "/name" only outside any element. A nil list means the
+// default.
+type MarkupOptions struct {
+  BlockElements []string // MARKUP_BLOCK_ELEMENTS
+  LineElements []string  // MARKUP_LINE_ELEMENTS
+  SkipElements []string  // MARKUP_SKIP_ELEMENTS
+}
+
+type markupElements map[string]bool
+
+func (set markupElements) has(name string, open []string) bool {
+  parent := ""
+  if len(open) > 0 {
+    parent = open[len(open)-1]
+  }
+
+  return set[name] || set[parent + "/" + name]
+}
+
+func markupElementSet(names, defaults []string) markupElements {
+  if names == nil {
+    names = defaults
+  }
+
+  set := make(markupElements, len(names))
+  for _, v := range names {
+    set[strings.ToLower(v)] = true
+  }
+
+  return set
+}
+
+// Splits the text of an HTML or XML document into words, with a
+// PARAGRAPH_BREAK at the start and end of each block element and a
+// LINE_BREAK at each line element, leaving out the content of skipped
+// elements. Markup is read leniently: unclosed elements, HTML entities and
+// stray < are fine.
+func SplitMarkupIntoMarkedWords(r io.Reader, options MarkupOptions) ([]string, error) {
+  data, err := ioutil.ReadAll(r)
+  if err != nil {
+    return nil, err
+  }
+
+  block := markupElementSet(options.BlockElements, MARKUP_BLOCK_ELEMENTS)
+  line := markupElementSet(options.LineElements, MARKUP_LINE_ELEMENTS)
+  skip := markupElementSet(options.SkipElements, MARKUP_SKIP_ELEMENTS)
+
+  src := lenientMarkup(data)
+  d := xml.NewDecoder(bytes.NewReader(src))
+  d.Strict = false
+  d.AutoClose = xml.HTMLAutoClose
+  d.Entity = xml.HTMLEntity
+
+  var w markupWords
+  var open []string
+  skipping := 0
+
+  for {
+    tok, err := d.Token()
+    if err == io.EOF || atEndOfMarkup(d, src, err) {
+      break
+    } else if err != nil {
+      return nil, fmt.Errorf("punkt: can't read markup: %w", err)
+    }
+
+    switch tok := tok.(type) {
+    case xml.StartElement:
+      name := strings.ToLower(tok.Name.Local)
+
+      if skipping > 0 || skip.has(name, open) {
+        skipping++
+      } else if block.has(name, open) {
+        w.mark(PARAGRAPH_BREAK)
+      } else if line.has(name, open) {
+        w.mark(LINE_BREAK)
+      }
+
+      open = append(open, name)
+    case xml.EndElement:
+      if len(open) > 0 {
+        open = open[:len(open)-1]
+      }
+
+      if skipping > 0 {
+        skipping--
+      } else if block.has(strings.ToLower(tok.Name.Local), open) {
+        w.mark(PARAGRAPH_BREAK)
+      }
+    case xml.CharData:
+      if skipping == 0 {
+        w.text.Write(tok)
+      }
+    }
+  }
+
+  w.flush()
+
+  // a break at the end separates nothing
+  if n := len(w.words); n > 0 && (w.words[n-1] == PARAGRAPH_BREAK || w.words[n-1] == LINE_BREAK) {
+    w.words = w.words[:n-1]
+  }
+
+  return w.words, nil
+}
+
+// Whether err only says that elements are still open at the end of the
+// input, as they often are in truncated or sloppy HTML
+func atEndOfMarkup(d *xml.Decoder, src []byte, err error) bool {
+  var syntaxErr *xml.SyntaxError
+  return errors.As(err, &syntaxErr) && d.InputOffset() >= int64(len(src))
+}
+
+// The words so far, and the text since the last break. Text is collected
+// across inline elements, so eg "<b>Dr</b>." is one word.
+type markupWords struct {
+  words []string
+  text strings.Builder
+}
+
+// Spaces such as &nbsp; separate words as any other
+func (w *markupWords) flush() {
+  text := strings.Map(func(r rune) rune {
+    if unicode.IsSpace(r) {
+      return ' '
+    }
+
+    return r
+  }, w.text.String())
+
+  w.words = append(w.words, SplitTextIntoWords(text)...)
+  w.text.Reset()
+}
+
+// Adds a break, unless it would follow another or start the document. A
+// paragraph break replaces a line break.
+func (w *markupWords) mark(marker string) {
+  w.flush()
+
+  n := len(w.words)
+  if n == 0 {
+    return
+  }
+
+  switch w.words[n-1] {
+  case PARAGRAPH_BREAK:
+    return
+  case LINE_BREAK:
+    if marker == PARAGRAPH_BREAK {
+      w.words[n-1] = marker
+    }
+    return
+  }
+
+  w.words = append(w.words, marker)
+}
+
+// Makes HTML readable by encoding/xml: drops the content of script and
+// style elements, and escapes any < that can't start a tag
+func lenientMarkup(data []byte) []byte {
+  var out bytes.Buffer
+  out.Grow(len(data))
+
+  for i := 0; i < len(data); i++ {
+    if data[i] != '<' {
+      out.WriteByte(data[i])
+      continue
+    }
+
+    if i + 1 == len(data) || !isMarkupTagStart(data[i+1]) {
+      out.WriteString("&lt;")
+      continue
+    }
+
+    name := markupRawTextElement(data[i+1:])
+    if name == "" {
+      out.WriteByte('<')
+      continue
+    }
+
+    // keep the start tag, and carry on from the end tag
+    gt := bytes.IndexByte(data[i:], '>')
+    if gt < 0 {
+      break
+    }
+
+    out.Write(data[i:i+gt+1])
+    i += gt
+
+    if data[i-1] == '/' {
+      continue
+    }
+
+    end := indexFoldASCII(data[i+1:], "</" + name)
+    if end < 0 {
+      break
+    }
+
+    i += end
+  }
+
+  return out.Bytes()
+}
+
+func isMarkupTagStart(c byte) bool {
+  return c == '/' || c == '!' || c == '?' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// The raw text element a start tag opens, if any
+func markupRawTextElement(tag []byte) string {
+  for _, name := range markupRawTextElements {
+    n := len(name)
+
+    if len(tag) > n && bytes.EqualFold(tag[:n], []byte(name)) &&
+       (tag[n] == '>' || tag[n] == '/' || tag[n] == ' ' || tag[n] == '\t' || tag[n] == '\n' || tag[n] == '\r') {
+      return name
+    }
+  }
+
+  return ""
+}
+
+// Like bytes.Index, ignoring case
+func indexFoldASCII(data []byte, s string) int {
+  b := []byte(s)
+
+  for i := 0; i + len(b) <= len(data); i++ {
+    if bytes.EqualFold(data[i:i+len(b)], b) {
+      return i
+    }
+  }
+
+  return -1
+}
+
+// Trains with the text of an HTML or XML document, see
+// SplitMarkupIntoMarkedWords
+func (t *Trainer) TrainWithMarkup(r io.Reader, options MarkupOptions) (*LanguageParameters, error) {
+  return t.TrainWithMarkupContext(context.Background(), r, options, nil)
+}
+
+func (t *Trainer) TrainWithMarkupContext(ctx context.Context, r io.Reader, options MarkupOptions, progress func(TrainingProgress)) (*LanguageParameters, error) {
+  words, err := SplitMarkupIntoMarkedWords(r, options)
+  if err != nil {
+    return nil, err
+  }
+
+  return t.TrainWithTokenizedTextContext(ctx, words, progress)
+}
@@ -0,0 +1,170 @@
+package punkt
+
+import (
+  "fmt"
+  "math"
+  "sort"
+  "sync"
+)
+
+// A test of how strongly two events are associated, eg a type and a
+// preceding sentence break. countA and countB are how often each event was
+// seen, countAB how often they were seen together and n the number of
+// tokens. Higher scores mean stronger association. The Trainer compares
+// scores with the cutoffs in its TrainerSettings, so a measure needs
+// cutoffs on its own scale.
+type AssociationMeasure interface {
+  Name() string
+  Score(countA, countB, countAB, n int) float64
+}
+
+type associationMeasureFunc struct {
+  name string
+  score func(countA, countB, countAB, n int) float64
+}
+
+func (m associationMeasureFunc) Name() string {
+  return m.name
+}
+
+func (m associationMeasureFunc) Score(countA, countB, countAB, n int) float64 {
+  return m.score(countA, countB, countAB, n)
+}
+
+// Names of the bundled measures, as used in TrainerSettings
+const (
+  MEASURE_DUNNING = "dunning"               // DunningLogLikelihood, for abbreviations
+  MEASURE_LOG_LIKELIHOOD = "log_likelihood" // ColLogLikelihood, cutoffs as the defaults
+  MEASURE_CHI_SQUARE = "chi_square"         // Pearson's chi-square, same scale as log_likelihood
+  MEASURE_PMI = "pmi"                       // pointwise mutual information in bits, eg 1 for twice as often as chance
+  MEASURE_T_SCORE = "t_score"               // eg 2.576 for p < 0.005
+  MEASURE_FISHER = "fisher"                 // -log10 of Fisher's exact p-value, eg 2.3 for p < 0.005
+)
+
+var (
+  associationMeasuresLock sync.RWMutex
+  associationMeasures = map[string]AssociationMeasure{
+    MEASURE_DUNNING: associationMeasureFunc{MEASURE_DUNNING, DunningLogLikelihood},
+    MEASURE_LOG_LIKELIHOOD: associationMeasureFunc{MEASURE_LOG_LIKELIHOOD, ColLogLikelihood},
+    MEASURE_CHI_SQUARE: associationMeasureFunc{MEASURE_CHI_SQUARE, ChiSquare},
+    MEASURE_PMI: associationMeasureFunc{MEASURE_PMI, PointwiseMutualInformation},
+    MEASURE_T_SCORE: associationMeasureFunc{MEASURE_T_SCORE, TScore},
+    MEASURE_FISHER: associationMeasureFunc{MEASURE_FISHER, FisherExact},
+  }
+)
+
+// Makes a measure available to TrainerSettings under its name, replacing
+// any measure of that name
+func RegisterAssociationMeasure(m AssociationMeasure) {
+  associationMeasuresLock.Lock()
+  defer associationMeasuresLock.Unlock()
+
+  associationMeasures[m.Name()] = m
+}
+
+func FindAssociationMeasure(name string) (AssociationMeasure, error) {
+  associationMeasuresLock.RLock()
+  defer associationMeasuresLock.RUnlock()
+
+  m, found := associationMeasures[name]
+  if !found {
+    return nil, fmt.Errorf("punkt: unknown association measure %q", name)
+  }
+
+  return m, nil
+}
+
+// The names of all registered measures, sorted
+func AssociationMeasures() []string {
+  associationMeasuresLock.RLock()
+  defer associationMeasuresLock.RUnlock()
+
+  names := make([]string, 0, len(associationMeasures))
+  for k := range associationMeasures {
+    names = append(names, k)
+  }
+
+  sort.Strings(names)
+  return names
+}
+
+// the observed and expected counts of a and b together
+func contingency(countA, countB, countAB, n int) (o11, o12, o21, o22, e11 float64) {
+  o11 = float64(countAB)
+  o12 = float64(countA - countAB)
+  o21 = float64(countB - countAB)
+  o22 = float64(n - countA - countB + countAB)
+  e11 = float64(countA) * float64(countB) / float64(n)
+  return
+}
+
+func ChiSquare(countA, countB, countAB, n int) float64 {
+  o11, o12, o21, o22, _ := contingency(countA, countB, countAB, n)
+  d := o11*o22 - o12*o21
+  denominator := (o11 + o12) * (o11 + o21) * (o12 + o22) * (o21 + o22)
+
+  if denominator == 0 {
+    return 0
+  }
+
+  return float64(n) * d * d / denominator
+}
+
+func PointwiseMutualInformation(countA, countB, countAB, n int) float64 {
+  o11, _, _, _, e11 := contingency(countA, countB, countAB, n)
+
+  if o11 == 0 || e11 == 0 {
+    return math.Inf(-1)
+  }
+
+  return math.Log2(o11 / e11)
+}
+
+func TScore(countA, countB, countAB, n int) float64 {
+  o11, _, _, _, e11 := contingency(countA, countB, countAB, n)
+
+  if o11 == 0 {
+    return 0
+  }
+
+  return (o11 - e11) / math.Sqrt(o11)
+}
+
+func logChoose(n, k int) float64 {
+  a, _ := math.Lgamma(float64(n + 1))
+  b, _ := math.Lgamma(float64(k + 1))
+  c, _ := math.Lgamma(float64(n - k + 1))
+  return a - b - c
+}
+
+// -log10 of the one sided p-value of seeing countAB or more together
+func FisherExact(countA, countB, countAB, n int) float64 {
+  if countA > n || countB > n || countAB > countA || countAB > countB || countA + countB - countAB > n {
+    return 0
+  }
+
+  max := countA
+  if countB < max {
+    max = countB
+  }
+
+  // the most likely count, after which the terms only get smaller
+  mode := (countA + 1) * (countB + 1) / (n + 2)
+  total := logChoose(n, countB)
+  p := 0.0
+
+  for k := countAB; k <= max; k++ {
+    term := math.Exp(logChoose(countA, k) + logChoose(n - countA, countB - k) - total)
+    p += term
+
+    if k > mode && term < p * 1e-17 {
+      break
+    }
+  }
+
+  if p >= 1 {
+    return 0
+  }
+
+  return -math.Log10(p)
+}
@@ -0,0 +1,58 @@
+package punkt
+
+import (
+  "time"
+)
+
+// Version of this library, recorded in the metadata of models it trains
+const VERSION = "0.2.0"
+
+// The settings a Trainer uses, see Trainer.SetSettings. The defaults come
+// from the constants in trainer.go. Measures are named as registered with
+// RegisterAssociationMeasure, and each cutoff is on the scale of its measure.
+type TrainerSettings struct {
+  AbbrevCutoff float64 `json:"abbrev_cutoff"`
+  IgnoreAbbrevPenalty bool `json:"ignore_abbrev_penalty"`
+  AbbrevBackoff int `json:"abbrev_backoff"`
+  CollocationCutoff float64 `json:"collocation_cutoff"`
+  SentStarterCutoff float64 `json:"sent_starter_cutoff"`
+  IncludeAllCollocs bool `json:"include_all_collocs"`
+  IncludeAbbrevCollocs bool `json:"include_abbrev_collocs"`
+  MinCollocFreq int `json:"min_colloc_freq"`
+  AbbrevMeasure string `json:"abbrev_measure,omitempty"`
+  SentStarterMeasure string `json:"sent_starter_measure,omitempty"`
+  CollocationMeasure string `json:"collocation_measure,omitempty"`
+}
+
+func DefaultTrainerSettings() TrainerSettings {
+  return TrainerSettings{
+    AbbrevCutoff: ABBREV_CUTOFF,
+    IgnoreAbbrevPenalty: IGNORE_ABBREV_PENALTY,
+    AbbrevBackoff: ABBREV_BACKOFF,
+    CollocationCutoff: COLLOCATION_CUTOFF,
+    SentStarterCutoff: SENT_STARTER_CUTOFF,
+    IncludeAllCollocs: INCLUDE_ALL_COLLOCS,
+    IncludeAbbrevCollocs: INCLUDE_ABBREV_COLLOCS,
+    MinCollocFreq: MIN_COLLOC_FREQ,
+    AbbrevMeasure: MEASURE_DUNNING,
+    SentStarterMeasure: MEASURE_LOG_LIKELIHOOD,
+    CollocationMeasure: MEASURE_LOG_LIKELIHOOD,
+  }
+}
+
+// Where a model came from. The Trainer fills in what it knows, the rest
+// (Language, Corpus) is up to whoever trains the model. It is kept in the
+// "metadata" key of JSON model files.
+type ModelMetadata struct {
+  Language string `json:"language,omitempty"` // BCP-47 tag, eg "en-US"
+  Corpus string `json:"corpus,omitempty"`
+  TokenCount int `json:"token_count,omitempty"`
+  TrainerSettings *TrainerSettings `json:"trainer_settings,omitempty"`
+  LibraryVersion string `json:"library_version,omitempty"`
+  CreatedAt time.Time `json:"created_at"`
+}
+
+func (m ModelMetadata) IsZero() bool {
+  return m.Language == "" && m.Corpus == "" && m.TokenCount == 0 &&
+         m.TrainerSettings == nil && m.LibraryVersion == "" && m.CreatedAt.IsZero()
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package punkt
+
+import (
+  "io/ioutil"
+  "os"
+)
+
+// no mmap here, so the file is read into memory
+func mapFile(f *os.File) ([]byte, func() error, error) {
+  buf, err := ioutil.ReadAll(f)
+  if err != nil {
+    return nil, nil, err
+  }
+
+  return buf, func() error { return nil }, nil
+}
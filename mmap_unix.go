@@ -0,0 +1,27 @@
+//go:build unix
+
+package punkt
+
+import (
+  "os"
+  "syscall"
+)
+
+func mapFile(f *os.File) ([]byte, func() error, error) {
+  info, err := f.Stat()
+  if err != nil {
+    return nil, nil, err
+  }
+
+  size := info.Size()
+  if size <= 0 || int64(int(size)) != size {
+    return nil, nil, ErrBadMappedModel
+  }
+
+  buf, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+  if err != nil {
+    return nil, nil, err
+  }
+
+  return buf, func() error { return syscall.Munmap(buf) }, nil
+}
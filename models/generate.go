@@ -0,0 +1,23 @@
+// Package models holds the bundled Punkt models compiled into Go, one package
+// per language (eg github.com/harrisj/punkt/models/english). The packages are
+// generated from the files in data/ with punktgen; run "make models" or
+// "go generate ./models" to create them.
+package models
+
+//go:generate go run ../cmd/punktgen -pkg czech -o czech/model.go ../data/czech.json
+//go:generate go run ../cmd/punktgen -pkg danish -o danish/model.go ../data/danish.json
+//go:generate go run ../cmd/punktgen -pkg dutch -o dutch/model.go ../data/dutch.json
+//go:generate go run ../cmd/punktgen -pkg english -o english/model.go ../data/english.json
+//go:generate go run ../cmd/punktgen -pkg estonian -o estonian/model.go ../data/estonian.json
+//go:generate go run ../cmd/punktgen -pkg finnish -o finnish/model.go ../data/finnish.json
+//go:generate go run ../cmd/punktgen -pkg french -o french/model.go ../data/french.json
+//go:generate go run ../cmd/punktgen -pkg german -o german/model.go ../data/german.json
+//go:generate go run ../cmd/punktgen -pkg greek -o greek/model.go ../data/greek.json
+//go:generate go run ../cmd/punktgen -pkg italian -o italian/model.go ../data/italian.json
+//go:generate go run ../cmd/punktgen -pkg norwegian -o norwegian/model.go ../data/norwegian.json
+//go:generate go run ../cmd/punktgen -pkg polish -o polish/model.go ../data/polish.json
+//go:generate go run ../cmd/punktgen -pkg portuguese -o portuguese/model.go ../data/portuguese.json
+//go:generate go run ../cmd/punktgen -pkg slovene -o slovene/model.go ../data/slovene.json
+//go:generate go run ../cmd/punktgen -pkg spanish -o spanish/model.go ../data/spanish.json
+//go:generate go run ../cmd/punktgen -pkg swedish -o swedish/model.go ../data/swedish.json
+//go:generate go run ../cmd/punktgen -pkg turkish -o turkish/model.go ../data/turkish.json
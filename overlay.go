@@ -0,0 +1,151 @@
+package punkt
+
+import (
+  "bufio"
+  "fmt"
+  "io"
+  "os"
+  "strings"
+)
+
+// An Overlay adds user supplied entries on top of a base ParameterSet without
+// copying or changing it. Entries in the overlay win over the base, and forced
+// non-abbreviations win over both. Since an Overlay is itself a ParameterSet,
+// overlays can be stacked.
+type Overlay struct {
+  Base ParameterSet
+  AbbrevTypes map[string]bool
+  NonAbbrevTypes map[string]bool
+  Collocations map[Collocation]bool
+  SentenceStarters map[string]bool
+}
+
+func NewOverlay(base ParameterSet) *Overlay {
+  return &Overlay{
+    Base: base,
+    AbbrevTypes: make(map[string]bool),
+    NonAbbrevTypes: make(map[string]bool),
+    Collocations: make(map[Collocation]bool),
+    SentenceStarters: make(map[string]bool),
+  }
+}
+
+func (o *Overlay) HasAbbrevType(s string) bool {
+  if o.NonAbbrevTypes[s] {
+    return false
+  }
+
+  return o.AbbrevTypes[s] || (o.Base != nil && o.Base.HasAbbrevType(s))
+}
+
+func (o *Overlay) HasCollocation(s1, s2 string) bool {
+  return o.Collocations[Collocation{s1, s2}] || (o.Base != nil && o.Base.HasCollocation(s1, s2))
+}
+
+func (o *Overlay) HasSentenceStarter(s string) bool {
+  return o.SentenceStarters[s] || (o.Base != nil && o.Base.HasSentenceStarter(s))
+}
+
+func (o *Overlay) GetOrthographicContext(s string) OrthoContext {
+  if o.Base == nil {
+    return 0
+  }
+
+  return o.Base.GetOrthographicContext(s)
+}
+
+// abbreviation types are stored lower case and without the final period
+func normalizeAbbrevType(s string) string {
+  return strings.TrimSuffix(strings.ToLower(s), ".")
+}
+
+func (o *Overlay) SaveAbbrevType(s string) {
+  s = normalizeAbbrevType(s)
+  delete(o.NonAbbrevTypes, s)
+  o.AbbrevTypes[s] = true
+}
+
+func (o *Overlay) SaveNonAbbrevType(s string) {
+  s = normalizeAbbrevType(s)
+  delete(o.AbbrevTypes, s)
+  o.NonAbbrevTypes[s] = true
+}
+
+func (o *Overlay) SaveCollocation(s1, s2 string) {
+  o.Collocations[Collocation{strings.ToLower(s1), strings.ToLower(s2)}] = true
+}
+
+func (o *Overlay) SaveSentenceStarter(s string) {
+  o.SentenceStarters[strings.ToLower(s)] = true
+}
+
+// Reads overlay entries from a simple text format. Each line holds one entry;
+// blank lines and lines starting with # are ignored. A line like
+// [sentence_starters] switches the section for the lines that follow. Lines
+// before any section header are abbreviation types, so a plain list of
+// abbreviations is a valid overlay file.
+//
+//   # legal abbreviations
+//   u.s.c
+//   cir.
+//   [non_abbrev_types]
+//   gol
+//   [collocations]
+//   jan 15
+//   [sentence_starters]
+//   however
+func (o *Overlay) Load(r io.Reader) error {
+  section := "abbrev_types"
+  scanner := bufio.NewScanner(r)
+  lineNo := 0
+
+  for scanner.Scan() {
+    lineNo++
+    line := strings.TrimSpace(scanner.Text())
+
+    if len(line) == 0 || strings.HasPrefix(line, "#") {
+      continue
+    }
+
+    if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+      section = strings.TrimSpace(line[1 : len(line)-1])
+      continue
+    }
+
+    switch section {
+    case "abbrev_types":
+      o.SaveAbbrevType(line)
+    case "non_abbrev_types":
+      o.SaveNonAbbrevType(line)
+    case "sentence_starters":
+      o.SaveSentenceStarter(line)
+    case "collocations":
+      fields := strings.Fields(line)
+      if len(fields) != 2 {
+        return fmt.Errorf("punkt: overlay line %d: collocation needs two types, got %q", lineNo, line)
+      }
+      o.SaveCollocation(fields[0], fields[1])
+    default:
+      return fmt.Errorf("punkt: overlay line %d: unknown section %q", lineNo, section)
+    }
+  }
+
+  return scanner.Err()
+}
+
+// Creates an overlay on base with the entries from a text file in the format
+// described for Load.
+func LoadOverlayFromFile(base ParameterSet, path string) (*Overlay, error) {
+  f, err := os.Open(path)
+  if err != nil {
+    return nil, err
+  }
+  defer f.Close()
+
+  o := NewOverlay(base)
+  if err := o.Load(f); err != nil {
+    return nil, fmt.Errorf("%s: %v", path, err)
+  }
+
+  return o, nil
+}
@@ -1,12 +1,13 @@
 package punkt
 
 import (
+  "context"
   "fmt"
   "strings"
   "io/ioutil"
+  "path/filepath"
   "sort"
   "regexp"
-  "net/http"
   "encoding/json"
 )
 
@@ -33,11 +34,21 @@ const (
   ORTHO_LC = ORTHO_BEG_LC + ORTHO_MID_LC + ORTHO_UNK_LC
 )
 
+// The read-only lookups used when annotating tokens. *LanguageParameters is the
+// usual implementation, but anything answering these can drive a Tokenizer.
+type ParameterSet interface {
+  HasAbbrevType(s string) bool
+  HasCollocation(s1, s2 string) bool
+  HasSentenceStarter(s string) bool
+  GetOrthographicContext(s string) OrthoContext
+}
+
 type LanguageParameters struct {
   AbbrevTypes map[string]bool
-  Collocations map[string]bool
+  Collocations map[Collocation]bool
   SentenceStarters map[string]bool
   OrthographicContext map[string]OrthoContext
+  Metadata ModelMetadata
 }
 
 func (p LanguageParameters) HasAbbrevType(s string) bool {
@@ -80,40 +91,85 @@ func (p *LanguageParameters) ClearSentenceStarters() {
   p.SentenceStarters = make(map[string]bool)
 }
 
-func collocationMapKey(s1, s2 string) (key string) {
-  key = fmt.Sprintf("%v|%v", s1, s2)
-  return
+// Two types that are known to occur together across a period, eg "jan. 15"
+type Collocation struct {
+  Type1 string
+  Type2 string
 }
 
-func collocationSplitKey(in string) (s1, s2 string) {
-  arr := strings.Split(in, "|")
+// Writes the collocation as "type1|type2", with any | or \ in the types
+// escaped with a backslash
+func (c Collocation) String() string {
+  return escapeCollocationType(c.Type1) + "|" + escapeCollocationType(c.Type2)
+}
 
-  if len(arr) == 2 {
-    s1 = arr[0]
-    s2 = arr[1]
+// Orders collocations by first type, then second type
+func (c Collocation) Less(o Collocation) bool {
+  if c.Type1 != o.Type1 {
+    return c.Type1 < o.Type1
   }
 
-  return
+  return c.Type2 < o.Type2
+}
+
+func escapeCollocationType(s string) string {
+  if !strings.ContainsAny(s, "|\\") {
+    return s
+  }
+
+  return strings.NewReplacer("\\", "\\\\", "|", "\\|").Replace(s)
+}
+
+// Parses the "type1|type2" form written by Collocation.String
+func ParseCollocation(s string) (Collocation, error) {
+  var parts [2]strings.Builder
+  part := 0
+
+  for i := 0; i < len(s); i++ {
+    switch s[i] {
+    case '\\':
+      if i + 1 == len(s) || (s[i+1] != '\\' && s[i+1] != '|') {
+        return Collocation{}, fmt.Errorf("collocation %q has a bad escape at byte %d", s, i)
+      }
+
+      i++
+      parts[part].WriteByte(s[i])
+    case '|':
+      if part == 1 {
+        return Collocation{}, fmt.Errorf("collocation %q is not two types separated by |", s)
+      }
+
+      part++
+    default:
+      parts[part].WriteByte(s[i])
+    }
+  }
+
+  if part != 1 {
+    return Collocation{}, fmt.Errorf("collocation %q is not two types separated by |", s)
+  }
+
+  return Collocation{parts[0].String(), parts[1].String()}, nil
 }
 
 func (p LanguageParameters) HasCollocation(s1, s2 string) bool {
-  return p.Collocations[collocationMapKey(s1,s2)]
+  return p.Collocations[Collocation{s1, s2}]
 }
 
 func (p *LanguageParameters) SaveCollocation(s1, s2 string) {
-  p.saveRawCollocation(collocationMapKey(s1,s2))
+  p.saveRawCollocation(Collocation{s1, s2})
 }
 
-func (p *LanguageParameters) saveRawCollocation(s string) {
+func (p *LanguageParameters) saveRawCollocation(c Collocation) {
     if len(p.Collocations) == 0 {
     p.ClearCollocations()
   }
 
-  p.Collocations[s] = true
+  p.Collocations[c] = true
 }
 
 func (p *LanguageParameters) ClearCollocations() {
-  p.Collocations = make(map[string]bool)
+  p.Collocations = make(map[Collocation]bool)
 }
 
 func (p *LanguageParameters) ClearOrthographicContext() {
@@ -148,36 +204,96 @@ func (p *LanguageParameters) DeleteOrthographicContext(s string, flag OrthoConte
   p.OrthographicContext[s] ^= flag
 }
 
+// Collocations are written as "type1|type2" strings, but the files in data/
+// store them as [type1, type2] pairs, so both are accepted when loading.
+// Malformed entries are skipped; LoadParametersFromJSONStrict reports them.
+type JsonCollocations []Collocation
+
+func (c *JsonCollocations) UnmarshalJSON(data []byte) error {
+  var raw []json.RawMessage
+  if err := json.Unmarshal(data, &raw); err != nil {
+    return err
+  }
+
+  out := make(JsonCollocations, 0, len(raw))
+
+  for _, r := range raw {
+    var key string
+    if err := json.Unmarshal(r, &key); err == nil {
+      if colloc, err := ParseCollocation(key); err == nil {
+        out = append(out, colloc)
+      }
+      continue
+    }
+
+    var pair []string
+    if err := json.Unmarshal(r, &pair); err != nil {
+      return err
+    }
+
+    if len(pair) == 2 {
+      out = append(out, Collocation{pair[0], pair[1]})
+    }
+  }
+
+  *c = out
+  return nil
+}
+
+func (c JsonCollocations) MarshalJSON() ([]byte, error) {
+  keys := make([]string, len(c))
+  for i, v := range c {
+    keys[i] = v.String()
+  }
+
+  return json.Marshal(keys)
+}
+
 type JsonParameters struct {
-  Sentence_starters []string
-  Abbrev_types []string
-  Collocations []string
-  Ortho_context map[string]OrthoContext
+  Sentence_starters []string `json:"sentence_starters"`
+  Abbrev_types []string `json:"abbrev_types"`
+  Collocations JsonCollocations `json:"collocations"`
+  Ortho_context map[string]OrthoContext `json:"ortho_context"`
+  Metadata *ModelMetadata `json:"metadata,omitempty"`
 }
 
 // This is a hack since I don't know how to just load from files in the repo, so will pull from Github
 func LoadLanguage(language string) (* LanguageParameters) {
+  p, err := loadLanguage(language)
+
+  if err != nil {
+    panic(err)
+  }
+
+  return p
+}
+
+// Loads a bundled language or a registered Variant
+func loadLanguage(language string) (*LanguageParameters, error) {
+  if v, found := lookupVariant(language); found {
+    return v.Resolve()
+  }
+
   path := fmt.Sprintf("data/%s.json", language)
   data, err := Asset(path)
 
   if err != nil {
-    panic(err)
+    return nil, err
   }
 
-  return LoadParametersFromJSONString(data)
+  return LoadParametersFromJSONString(data), nil
 }
 
 func LoadParametersFromJSON(path string) (* LanguageParameters) {
   urlRegexp := regexp.MustCompile("^http(s?)://")
 
   if urlRegexp.MatchString(path) {
-    // load from URL
-    resp, err := http.Get(path)
+    // load from URL; use a ModelFetcher for caching and checksums
+    contents, err := defaultFetcher.Fetch(context.Background(), path, "")
     if err != nil {
       panic(err)
     }
-    defer resp.Body.Close()
-    contents, err := ioutil.ReadAll(resp.Body)
+
     return LoadParametersFromJSONString(contents)
   } else {
     contents, err := ioutil.ReadFile(path)
@@ -189,6 +305,41 @@ func LoadParametersFromJSON(path string) (* LanguageParameters) {
   }
 }
 
+// Loads a model file, picking the format from the file extension: JSON, or
+// the memory mapped format (read fully into LanguageParameters). Unlike
+// LoadParametersFromJSON this returns an error instead of panicking, and
+// JSON models are validated with LoadParametersFromJSONStrict.
+func LoadParametersFromFile(path string) (*LanguageParameters, error) {
+  contents, err := ioutil.ReadFile(path)
+  if err != nil {
+    return nil, err
+  }
+
+  return loadParametersFromContents(path, contents)
+}
+
+// the contents of the file at path, in the format of its extension
+func loadParametersFromContents(path string, contents []byte) (*LanguageParameters, error) {
+  switch strings.ToLower(filepath.Ext(path)) {
+  case ".json":
+    p, err := LoadParametersFromJSONStrict(contents)
+    if err != nil {
+      return nil, fmt.Errorf("%s: %v", path, err)
+    }
+
+    return p, nil
+  case MAPPED_MODEL_EXT:
+    m, err := NewMappedParameters(contents)
+    if err != nil {
+      return nil, fmt.Errorf("%s: %v", path, err)
+    }
+
+    return m.Tables().Parameters(), nil
+  default:
+    return nil, fmt.Errorf("punkt: unknown model format %q", filepath.Ext(path))
+  }
+}
+
 func LoadParametersFromJSONString(contents []byte) (* LanguageParameters) {
   var m JsonParameters
 
@@ -213,9 +364,44 @@ func LoadParametersFromJSONString(contents []byte) (* LanguageParameters) {
     p.SetOrthographicContext(k, v)
   }
 
+  if m.Metadata != nil {
+    p.Metadata = *m.Metadata
+  }
+
   return p
 }
 
+// Serializes the parameters in the same JSON format as the files in data/.
+// Lists are sorted so the same parameters always produce the same file.
+func (p LanguageParameters) ToJSON() ([]byte, error) {
+  m := JsonParameters{
+    Sentence_starters: sortedKeys(p.SentenceStarters),
+    Abbrev_types: sortedKeys(p.AbbrevTypes),
+    Collocations: sortedCollocations(p.Collocations),
+    Ortho_context: p.OrthographicContext,
+  }
+
+  if m.Ortho_context == nil {
+    m.Ortho_context = map[string]OrthoContext{}
+  }
+
+  if !p.Metadata.IsZero() {
+    meta := p.Metadata
+    m.Metadata = &meta
+  }
+
+  return json.Marshal(m)
+}
+
+func SaveParametersToJSON(p *LanguageParameters, path string) error {
+  contents, err := p.ToJSON()
+  if err != nil {
+    return err
+  }
+
+  return ioutil.WriteFile(path, contents, 0644)
+}
+
 func (p LanguageParameters) InspectSet(pSet map[string]bool) (out string) {
   var keys []string
 
@@ -233,6 +419,11 @@ func (p LanguageParameters) InspectSet(pSet map[string]bool) (out string) {
 }
 
 func (p LanguageParameters) String() (out string) {
-  return fmt.Sprintf("LP Abbrev: %s\nColloc: %s\nSentStart: %s\nOrtho: %#v", p.InspectSet(p.AbbrevTypes), p.InspectSet(p.Collocations), p.InspectSet(p.SentenceStarters), p.OrthographicContext)
+  collocations := make(map[string]bool, len(p.Collocations))
+  for k, v := range p.Collocations {
+    collocations[k.String()] = v
+  }
+
+  return fmt.Sprintf("LP Abbrev: %s\nColloc: %s\nSentStart: %s\nOrtho: %#v", p.InspectSet(p.AbbrevTypes), p.InspectSet(collocations), p.InspectSet(p.SentenceStarters), p.OrthographicContext)
 }
 
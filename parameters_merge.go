@@ -0,0 +1,381 @@
+package punkt
+
+import (
+  "fmt"
+  "sort"
+  "strings"
+)
+
+// How conflicting orthographic context flags are resolved when two sets of
+// parameters both have an entry for the same type.
+type OrthoMergeRule byte
+
+const (
+  ORTHO_MERGE_UNION OrthoMergeRule = iota // keep the flags seen in either model
+  ORTHO_MERGE_INTERSECT                   // keep only the flags seen in both models
+  ORTHO_MERGE_LEFT                        // keep the flags of the receiver
+  ORTHO_MERGE_RIGHT                       // keep the flags of the other model
+)
+
+var orthoFlagNames = []struct {
+  Flag OrthoContext
+  Name string
+}{
+  {ORTHO_BEG_UC, "BEG_UC"},
+  {ORTHO_MID_UC, "MID_UC"},
+  {ORTHO_UNK_UC, "UNK_UC"},
+  {ORTHO_BEG_LC, "BEG_LC"},
+  {ORTHO_MID_LC, "MID_LC"},
+  {ORTHO_UNK_LC, "UNK_LC"},
+}
+
+// Readable form of a set of flags, eg "BEG_UC|MID_LC"
+func (o OrthoContext) String() string {
+  if o == 0 {
+    return "0"
+  }
+
+  names := make([]string, 0, len(orthoFlagNames))
+  rest := o
+
+  for _, f := range orthoFlagNames {
+    if o & f.Flag != 0 {
+      names = append(names, f.Name)
+      rest &^= f.Flag
+    }
+  }
+
+  if rest != 0 {
+    names = append(names, fmt.Sprintf("0x%x", uint32(rest)))
+  }
+
+  return strings.Join(names, "|")
+}
+
+func (r OrthoMergeRule) resolve(a, b OrthoContext) OrthoContext {
+  switch r {
+  case ORTHO_MERGE_INTERSECT:
+    return a & b
+  case ORTHO_MERGE_LEFT:
+    return a
+  case ORTHO_MERGE_RIGHT:
+    return b
+  default:
+    return a | b
+  }
+}
+
+func sortedKeys(set map[string]bool) []string {
+  keys := make([]string, 0, len(set))
+
+  for k, v := range set {
+    if v {
+      keys = append(keys, k)
+    }
+  }
+
+  sort.Strings(keys)
+  return keys
+}
+
+// Sorted by first type, then second type
+func sortedCollocations(set map[Collocation]bool) []Collocation {
+  keys := make([]Collocation, 0, len(set))
+
+  for k, v := range set {
+    if v {
+      keys = append(keys, k)
+    }
+  }
+
+  sort.Slice(keys, func(i, j int) bool {
+    return keys[i].Less(keys[j])
+  })
+
+  return keys
+}
+
+// Returns a deep copy of the parameters
+func (p LanguageParameters) Copy() *LanguageParameters {
+  out := new(LanguageParameters)
+
+  for k := range p.AbbrevTypes {
+    out.SaveAbbrevType(k)
+  }
+
+  for k := range p.Collocations {
+    out.saveRawCollocation(k)
+  }
+
+  for k := range p.SentenceStarters {
+    out.SaveSentenceStarter(k)
+  }
+
+  for k, v := range p.OrthographicContext {
+    out.SetOrthographicContext(k, v)
+  }
+
+  out.Metadata = p.Metadata
+  if p.Metadata.TrainerSettings != nil {
+    settings := *p.Metadata.TrainerSettings
+    out.Metadata.TrainerSettings = &settings
+  }
+
+  return out
+}
+
+// Returns new parameters containing every entry of either model. Types with
+// orthographic context in both models are combined with the given rule.
+func (p LanguageParameters) Union(other *LanguageParameters, rule OrthoMergeRule) *LanguageParameters {
+  out := p.Copy()
+  out.Metadata = ModelMetadata{}
+
+  for k := range other.AbbrevTypes {
+    out.SaveAbbrevType(k)
+  }
+
+  for k := range other.Collocations {
+    out.saveRawCollocation(k)
+  }
+
+  for k := range other.SentenceStarters {
+    out.SaveSentenceStarter(k)
+  }
+
+  for k, v := range other.OrthographicContext {
+    if mine, ok := p.OrthographicContext[k]; ok {
+      v = rule.resolve(mine, v)
+    }
+
+    if v == 0 {
+      delete(out.OrthographicContext, k)
+    } else {
+      out.SetOrthographicContext(k, v)
+    }
+  }
+
+  return out
+}
+
+// Returns new parameters containing only the entries found in both models.
+// Orthographic context flags are combined with the given rule.
+func (p LanguageParameters) Intersect(other *LanguageParameters, rule OrthoMergeRule) *LanguageParameters {
+  out := new(LanguageParameters)
+
+  for k := range p.AbbrevTypes {
+    if other.HasAbbrevType(k) {
+      out.SaveAbbrevType(k)
+    }
+  }
+
+  for k := range p.Collocations {
+    if other.Collocations[k] {
+      out.saveRawCollocation(k)
+    }
+  }
+
+  for k := range p.SentenceStarters {
+    if other.HasSentenceStarter(k) {
+      out.SaveSentenceStarter(k)
+    }
+  }
+
+  for k, v := range p.OrthographicContext {
+    if theirs, ok := other.OrthographicContext[k]; ok {
+      if flags := rule.resolve(v, theirs); flags != 0 {
+        out.SetOrthographicContext(k, flags)
+      }
+    }
+  }
+
+  return out
+}
+
+// Returns new parameters with every entry of the other model removed. For
+// orthographic context only the flags set in the other model are cleared.
+func (p LanguageParameters) Subtract(other *LanguageParameters) *LanguageParameters {
+  out := new(LanguageParameters)
+
+  for k := range p.AbbrevTypes {
+    if !other.HasAbbrevType(k) {
+      out.SaveAbbrevType(k)
+    }
+  }
+
+  for k := range p.Collocations {
+    if !other.Collocations[k] {
+      out.saveRawCollocation(k)
+    }
+  }
+
+  for k := range p.SentenceStarters {
+    if !other.HasSentenceStarter(k) {
+      out.SaveSentenceStarter(k)
+    }
+  }
+
+  for k, v := range p.OrthographicContext {
+    if flags := v &^ other.OrthographicContext[k]; flags != 0 {
+      out.SetOrthographicContext(k, flags)
+    }
+  }
+
+  return out
+}
+
+type OrthoChange struct {
+  Type string
+  Old OrthoContext
+  New OrthoContext
+}
+
+// The changes needed to go from one set of parameters to another. All lists are sorted.
+type ParametersDiff struct {
+  AbbrevTypesAdded []string
+  AbbrevTypesRemoved []string
+  CollocationsAdded []Collocation
+  CollocationsRemoved []Collocation
+  SentenceStartersAdded []string
+  SentenceStartersRemoved []string
+  OrthoChanged []OrthoChange
+}
+
+func diffSets(from, to map[string]bool) (added, removed []string) {
+  for _, k := range sortedKeys(to) {
+    if !from[k] {
+      added = append(added, k)
+    }
+  }
+
+  for _, k := range sortedKeys(from) {
+    if !to[k] {
+      removed = append(removed, k)
+    }
+  }
+
+  return
+}
+
+func diffCollocations(from, to map[Collocation]bool) (added, removed []Collocation) {
+  for _, k := range sortedCollocations(to) {
+    if !from[k] {
+      added = append(added, k)
+    }
+  }
+
+  for _, k := range sortedCollocations(from) {
+    if !to[k] {
+      removed = append(removed, k)
+    }
+  }
+
+  return
+}
+
+func collocationStrings(list []Collocation) []string {
+  out := make([]string, len(list))
+  for i, v := range list {
+    out[i] = v.String()
+  }
+
+  return out
+}
+
+// Lists what was added, removed or changed in other compared to p
+func (p LanguageParameters) Diff(other *LanguageParameters) (d ParametersDiff) {
+  d.AbbrevTypesAdded, d.AbbrevTypesRemoved = diffSets(p.AbbrevTypes, other.AbbrevTypes)
+  d.CollocationsAdded, d.CollocationsRemoved = diffCollocations(p.Collocations, other.Collocations)
+  d.SentenceStartersAdded, d.SentenceStartersRemoved = diffSets(p.SentenceStarters, other.SentenceStarters)
+
+  types := map[string]bool{}
+  for k := range p.OrthographicContext {
+    types[k] = true
+  }
+  for k := range other.OrthographicContext {
+    types[k] = true
+  }
+
+  for _, k := range sortedKeys(types) {
+    before := p.OrthographicContext[k]
+    after := other.OrthographicContext[k]
+
+    if before != after {
+      d.OrthoChanged = append(d.OrthoChanged, OrthoChange{Type: k, Old: before, New: after})
+    }
+  }
+
+  return
+}
+
+// Returns a copy of p with the changes made, so that p.Diff(other).Apply(p)
+// gives the same parameters as other. Metadata is kept from p.
+func (d ParametersDiff) Apply(p *LanguageParameters) *LanguageParameters {
+  out := p.Copy()
+
+  for _, k := range d.AbbrevTypesAdded {
+    out.SaveAbbrevType(k)
+  }
+  for _, k := range d.AbbrevTypesRemoved {
+    out.DeleteAbbrevType(k)
+  }
+
+  for _, k := range d.CollocationsAdded {
+    out.saveRawCollocation(k)
+  }
+  for _, k := range d.CollocationsRemoved {
+    delete(out.Collocations, k)
+  }
+
+  for _, k := range d.SentenceStartersAdded {
+    out.SaveSentenceStarter(k)
+  }
+  for _, k := range d.SentenceStartersRemoved {
+    delete(out.SentenceStarters, k)
+  }
+
+  for _, c := range d.OrthoChanged {
+    if c.New == 0 {
+      delete(out.OrthographicContext, c.Type)
+    } else {
+      out.SetOrthographicContext(c.Type, c.New)
+    }
+  }
+
+  return out
+}
+
+func (d ParametersDiff) IsEmpty() bool {
+  return len(d.AbbrevTypesAdded) == 0 && len(d.AbbrevTypesRemoved) == 0 &&
+         len(d.CollocationsAdded) == 0 && len(d.CollocationsRemoved) == 0 &&
+         len(d.SentenceStartersAdded) == 0 && len(d.SentenceStartersRemoved) == 0 &&
+         len(d.OrthoChanged) == 0
+}
+
+// A readable listing of the diff, one change per line
+func (d ParametersDiff) String() (out string) {
+  sections := []struct {
+    Name string
+    Added []string
+    Removed []string
+  }{
+    {"abbrev_types", d.AbbrevTypesAdded, d.AbbrevTypesRemoved},
+    {"collocations", collocationStrings(d.CollocationsAdded), collocationStrings(d.CollocationsRemoved)},
+    {"sentence_starters", d.SentenceStartersAdded, d.SentenceStartersRemoved},
+  }
+
+  for _, s := range sections {
+    for _, v := range s.Added {
+      out += fmt.Sprintf("+ %s %q\n", s.Name, v)
+    }
+
+    for _, v := range s.Removed {
+      out += fmt.Sprintf("- %s %q\n", s.Name, v)
+    }
+  }
+
+  for _, c := range d.OrthoChanged {
+    out += fmt.Sprintf("~ ortho_context %q %v -> %v\n", c.Type, c.Old, c.New)
+  }
+
+  return
+}
@@ -0,0 +1,177 @@
+package punkt
+
+import (
+  "context"
+  "fmt"
+)
+
+type TrainingPhase string
+
+const (
+  PHASE_COUNTING TrainingPhase = "counting"           // making and counting tokens
+  PHASE_ABBREVIATIONS TrainingPhase = "abbreviations" // reclassifying abbreviation types
+  PHASE_ORTHOGRAPHY TrainingPhase = "orthography"     // building the orthographic context tables
+  PHASE_CANDIDATES TrainingPhase = "candidates"       // counting sentence starter and collocation candidates
+  PHASE_FINALIZE TrainingPhase = "finalize"           // scoring sentence starters and collocations
+)
+
+// tokens (or types, or candidates) between progress reports
+const TRAINING_PROGRESS_INTERVAL = 10000
+
+// how often a training run checks whether it was canceled
+const trainingCancelInterval = 1024
+
+// Passed to the progress callback of the Trainer's Context methods at the
+// start and end of each phase and every TRAINING_PROGRESS_INTERVAL items.
+// Processed and Total count tokens, except in the abbreviations phase
+// (types) and the finalize phase (candidates).
+type TrainingProgress struct {
+  Phase TrainingPhase
+  Processed int
+  Total int
+
+  // the sizes of the tables so far
+  Types int
+  AbbrevTypes int
+  OrthoTypes int
+  SentenceStarterCandidates int
+  CollocationCandidates int
+}
+
+// Returned when the context of a training run is done. The Trainer is left
+// as it was before the run, so it can be trained again.
+type TrainingCanceledError struct {
+  Phase TrainingPhase
+  Processed int
+  Err error
+}
+
+func (e *TrainingCanceledError) Error() string {
+  return fmt.Sprintf("punkt: training canceled in the %s phase after %d items: %v", e.Phase, e.Processed, e.Err)
+}
+
+func (e *TrainingCanceledError) Unwrap() error {
+  return e.Err
+}
+
+// Progress reporting and cancellation for one training run. A nil run does
+// neither, for the methods without a context.
+type trainingRun struct {
+  t *Trainer
+  ctx context.Context
+  progress func(TrainingProgress)
+  parameters *LanguageParameters
+
+  phase TrainingPhase
+  total int
+}
+
+func (r *trainingRun) report(processed int) {
+  if r.progress == nil {
+    return
+  }
+
+  p := TrainingProgress{
+    Phase: r.phase,
+    Processed: processed,
+    Total: r.total,
+    Types: len(r.t.TypeFdist.Counts),
+    SentenceStarterCandidates: len(r.t.SentenceStarterFdist.Counts),
+    CollocationCandidates: len(r.t.CollocationFdist.Counts),
+  }
+
+  if r.parameters != nil {
+    p.AbbrevTypes = len(r.parameters.AbbrevTypes)
+    p.OrthoTypes = len(r.parameters.OrthographicContext)
+  }
+
+  r.progress(p)
+}
+
+func (r *trainingRun) canceled(processed int) error {
+  if err := r.ctx.Err(); err != nil {
+    return &TrainingCanceledError{r.phase, processed, err}
+  }
+
+  return nil
+}
+
+func (r *trainingRun) use(parameters *LanguageParameters) {
+  if r != nil {
+    r.parameters = parameters
+  }
+}
+
+// Starts a phase of total items
+func (r *trainingRun) start(phase TrainingPhase, total int) error {
+  if r == nil {
+    return nil
+  }
+
+  r.phase, r.total = phase, total
+  if err := r.canceled(0); err != nil {
+    return err
+  }
+
+  r.report(0)
+  return nil
+}
+
+// Called before each item of the phase is processed
+func (r *trainingRun) step(processed int) error {
+  if r == nil || processed == 0 {
+    return nil
+  }
+
+  if processed % trainingCancelInterval == 0 {
+    if err := r.canceled(processed); err != nil {
+      return err
+    }
+  }
+
+  if processed % TRAINING_PROGRESS_INTERVAL == 0 {
+    r.report(processed)
+  }
+
+  return nil
+}
+
+// Ends the phase, so a run canceled during a short phase still stops
+func (r *trainingRun) finish() error {
+  if r == nil {
+    return nil
+  }
+
+  r.report(r.total)
+  return r.canceled(r.total)
+}
+
+// Runs train, and puts the Trainer back as it was if it fails. The
+// statistics are only copied if the context can be canceled.
+func (t *Trainer) runTraining(ctx context.Context, progress func(TrainingProgress), train func(r *trainingRun) (*LanguageParameters, error)) (*LanguageParameters, error) {
+  if ctx.Done() == nil {
+    return train(&trainingRun{t: t, ctx: ctx, progress: progress})
+  }
+
+  saved := t.copyState()
+
+  p, err := train(&trainingRun{t: t, ctx: ctx, progress: progress})
+  if err != nil {
+    *t = saved
+    return nil, err
+  }
+
+  return p, nil
+}
+
+// A copy of the Trainer that shares nothing that training changes
+func (t *Trainer) copyState() Trainer {
+  c := *t
+  c.TypeFdist = t.TypeFdist.Copy()
+  c.CollocationFdist = t.CollocationFdist.Copy()
+  c.SentenceStarterFdist = t.SentenceStarterFdist.Copy()
+  c.GoldAbbrevFdist = t.GoldAbbrevFdist.Copy()
+  c.GoldFinalFdist = t.GoldFinalFdist.Copy()
+  c.report.Candidates = append([]TrainingCandidate(nil), t.report.Candidates...)
+  return c
+}
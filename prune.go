@@ -0,0 +1,159 @@
+package punkt
+
+import (
+  "fmt"
+)
+
+// What to remove when pruning a model. Zero values turn a rule off.
+type PruneOptions struct {
+  // Orthographic context entries for types seen fewer than MinOrthoCount
+  // times are removed. The counts come from TypeCounts, or from the
+  // Trainer's TypeFdist if TypeCounts is not set.
+  TypeCounts *FrequencyDistribution[string]
+  MinOrthoCount int
+
+  // The Trainer that produced the model. Collocations and sentence starters
+  // scoring below these log-likelihoods in its statistics are removed.
+  Trainer *Trainer
+  MinCollocationScore float64
+  MinStarterScore float64
+
+  // Removes orthographic context entries that cannot change the outcome of
+  // AnnotateSecondPass. Only the lower case flags are ever tested there, so
+  // a type that was only seen upper case behaves exactly like an unknown one.
+  RemoveIneffective bool
+
+  // If set, the pruned model must split this text into the same sentences as
+  // the original one.
+  Sample string
+}
+
+type PruneReport struct {
+  OrthoRemoved int
+  CollocationsRemoved int
+  SentenceStartersRemoved int
+
+  // size of the model as JSON
+  BytesBefore int
+  BytesAfter int
+}
+
+func (r PruneReport) BytesSaved() int {
+  return r.BytesBefore - r.BytesAfter
+}
+
+func (r PruneReport) String() string {
+  return fmt.Sprintf("removed %d ortho entries, %d collocations, %d sentence starters; %d -> %d bytes (saved %d)",
+    r.OrthoRemoved, r.CollocationsRemoved, r.SentenceStartersRemoved, r.BytesBefore, r.BytesAfter, r.BytesSaved())
+}
+
+// Returned by Prune when the pruned model splits PruneOptions.Sample differently
+type SegmentationChangedError struct {
+  Index int
+  Before string
+  After string
+}
+
+func (e *SegmentationChangedError) Error() string {
+  return fmt.Sprintf("punkt: pruning changed sentence %d of the sample from %q to %q", e.Index, e.Before, e.After)
+}
+
+// Returns a pruned copy of the parameters and what was removed. The
+// original parameters are not changed.
+func Prune(p *LanguageParameters, opts PruneOptions) (*LanguageParameters, PruneReport, error) {
+  var report PruneReport
+  out := p.Copy()
+
+  counts := opts.TypeCounts
+  if counts == nil && opts.Trainer != nil {
+    counts = &opts.Trainer.TypeFdist
+  }
+
+  if opts.MinOrthoCount > 0 && counts == nil {
+    return nil, report, fmt.Errorf("punkt: pruning by MinOrthoCount needs TypeCounts or a Trainer")
+  }
+
+  if (opts.MinCollocationScore > 0 || opts.MinStarterScore > 0) && opts.Trainer == nil {
+    return nil, report, fmt.Errorf("punkt: pruning by score needs the Trainer")
+  }
+
+  for k, v := range p.OrthographicContext {
+    remove := opts.RemoveIneffective && v & ORTHO_LC == 0
+
+    if opts.MinOrthoCount > 0 && counts.Get(k) + counts.Get(k + ".") < opts.MinOrthoCount {
+      remove = true
+    }
+
+    if remove {
+      delete(out.OrthographicContext, k)
+      report.OrthoRemoved++
+    }
+  }
+
+  if opts.MinCollocationScore > 0 {
+    for k := range p.Collocations {
+      if opts.Trainer.CollocationScore(k.Type1, k.Type2) < opts.MinCollocationScore {
+        delete(out.Collocations, k)
+        report.CollocationsRemoved++
+      }
+    }
+  }
+
+  if opts.MinStarterScore > 0 {
+    for k := range p.SentenceStarters {
+      if opts.Trainer.SentenceStarterScore(k) < opts.MinStarterScore {
+        delete(out.SentenceStarters, k)
+        report.SentenceStartersRemoved++
+      }
+    }
+  }
+
+  before, err := p.ToJSON()
+  if err != nil {
+    return nil, report, err
+  }
+
+  after, err := out.ToJSON()
+  if err != nil {
+    return nil, report, err
+  }
+
+  report.BytesBefore = len(before)
+  report.BytesAfter = len(after)
+
+  if opts.Sample != "" {
+    if err := compareSegmentation(p, out, opts.Sample); err != nil {
+      return nil, report, err
+    }
+  }
+
+  return out, report, nil
+}
+
+func compareSegmentation(original, pruned ParameterSet, text string) error {
+  var t Tokenizer
+
+  t.SetParameterSet(original)
+  before := t.SentencesFromText(text)
+
+  t.SetParameterSet(pruned)
+  after := t.SentencesFromText(text)
+
+  for i := 0; i < len(before) || i < len(after); i++ {
+    var b, a string
+
+    if i < len(before) {
+      b = before[i]
+    }
+
+    if i < len(after) {
+      a = after[i]
+    }
+
+    if a != b {
+      return &SegmentationChangedError{Index: i, Before: b, After: a}
+    }
+  }
+
+  return nil
+}
@@ -106,31 +106,84 @@ func SplitTextIntoWords(input string) (tokens []string) {
 	return
 }
 
-func TokenizeText(plainText string) []*Token {
-	paragraphStart := false
+// Markers between words for MakeTokens and TrainWithTokenizedText. The word
+// after a LINE_BREAK starts a line, and the word after a PARAGRAPH_BREAK or a
+// blank line (two LINE_BREAKs) starts a paragraph.
+const (
+	LINE_BREAK      = "\n"
+	PARAGRAPH_BREAK = "\n\n"
+)
 
-	lines := strings.Split(plainText, "\n")
-	out := make([]*Token, 0)
+// Splits text into words line by line, with a LINE_BREAK between lines
+func SplitTextIntoMarkedWords(plainText string) []string {
+	out := make([]string, 0)
 
-	for _, line := range lines {
-		if len(line) == 0 {
-			paragraphStart = true
-		} else {
-			words := SplitTextIntoWords(line)
+	for i, line := range strings.Split(plainText, "\n") {
+		if i > 0 {
+			out = append(out, LINE_BREAK)
+		}
 
-			for j, v := range words {
-				t := MakeToken(v)
+		out = append(out, SplitTextIntoWords(line)...)
+	}
 
-				if j == 0 {
-					t.SetParagraphStart(paragraphStart)
-					t.SetLineStart(true)
-				}
+	return out
+}
 
-				paragraphStart = false
-				out = append(out, t)
-			}
+// Tracks the markers between words. The first word starts a line.
+type lineMarks struct {
+	lineStart      bool
+	paragraphStart bool
+}
+
+func newLineMarks() lineMarks {
+	return lineMarks{lineStart: true}
+}
+
+// Whether the word is a marker, which is kept for the next token
+func (m *lineMarks) skip(word string) bool {
+	switch word {
+	case LINE_BREAK:
+		if m.lineStart {
+			m.paragraphStart = true
 		}
+
+		m.lineStart = true
+	case PARAGRAPH_BREAK:
+		m.lineStart, m.paragraphStart = true, true
+	default:
+		return false
 	}
 
-	return out 
+	return true
+}
+
+func (m *lineMarks) mark(t *Token) {
+	t.SetLineStart(m.lineStart)
+	t.SetParagraphStart(m.paragraphStart)
+	m.lineStart, m.paragraphStart = false, false
+}
+
+// Makes tokens from words, with line and paragraph starts where the
+// markers say
+func MakeTokens(words []string) []*Token {
+	marks := newLineMarks()
+	out := make([]*Token, 0, len(words))
+
+	for _, v := range words {
+		if marks.skip(v) {
+			continue
+		}
+
+		t := MakeToken(v)
+		marks.mark(t)
+		out = append(out, t)
+	}
+
+	return out
+}
+
+// Tokens for the words of the text, with line and paragraph starts. A line
+// with no words is blank, so the next line starts a paragraph.
+func TokenizeText(plainText string) []*Token {
+	return MakeTokens(SplitTextIntoMarkedWords(plainText))
 }
@@ -0,0 +1,259 @@
+package punkt
+
+import (
+  "context"
+  "crypto/sha256"
+  "encoding/hex"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "sort"
+  "sync"
+  "sync/atomic"
+  "time"
+)
+
+// One version of a model held by a Registry. It is never changed after it is
+// loaded, so it can be used while a newer version is swapped in.
+type LoadedModel struct {
+  Name string
+  Path string
+  Parameters *LanguageParameters
+  Version string // SHA-256 of the model file
+  ModTime time.Time
+  LoadedAt time.Time
+}
+
+// What a Registry knows about one model
+type ModelStatus struct {
+  Current *LoadedModel
+
+  // the last failed reload, if it came after the current version was loaded
+  LastError error
+  LastErrorAt time.Time
+}
+
+type registryEntry struct {
+  path string
+  current atomic.Pointer[LoadedModel]
+
+  // guards the fields below, and makes reloads of one entry take turns
+  mu sync.Mutex
+  modTime time.Time
+  size int64
+  failedModTime time.Time
+  failedSize int64
+  lastError error
+  lastErrorAt time.Time
+}
+
+// Holds named models loaded from files, for services that run for a long
+// time. Poll or Watch reload the files that changed; each new version is
+// loaded with LoadParametersFromFile and checked with Check before it
+// replaces the old one, and if anything fails the old version stays in use.
+type Registry struct {
+  // optional extra check on new versions, eg a Lint gate
+  Check func(name string, p *LanguageParameters) error
+
+  // called by Watch for each failed reload
+  OnError func(name string, err error)
+
+  mu sync.RWMutex
+  entries map[string]*registryEntry
+}
+
+func NewRegistry() *Registry {
+  return &Registry{entries: map[string]*registryEntry{}}
+}
+
+// Loads the model at path under name, replacing any model of that name. It
+// returns an error and adds nothing if the model can't be loaded.
+func (r *Registry) Load(name, path string) error {
+  e := &registryEntry{path: path}
+
+  if _, err := r.reload(name, e); err != nil {
+    return err
+  }
+
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  if r.entries == nil {
+    r.entries = map[string]*registryEntry{}
+  }
+
+  r.entries[name] = e
+  return nil
+}
+
+func (r *Registry) Remove(name string) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  delete(r.entries, name)
+}
+
+func (r *Registry) entry(name string) (*registryEntry, bool) {
+  r.mu.RLock()
+  defer r.mu.RUnlock()
+
+  e, found := r.entries[name]
+  return e, found
+}
+
+// The current version of a model
+func (r *Registry) Get(name string) (*LoadedModel, bool) {
+  e, found := r.entry(name)
+  if !found {
+    return nil, false
+  }
+
+  return e.current.Load(), true
+}
+
+// The current parameters of a model, or nil if there is no such model
+func (r *Registry) Parameters(name string) *LanguageParameters {
+  m, found := r.Get(name)
+  if !found {
+    return nil
+  }
+
+  return m.Parameters
+}
+
+func (r *Registry) Status(name string) (ModelStatus, bool) {
+  e, found := r.entry(name)
+  if !found {
+    return ModelStatus{}, false
+  }
+
+  e.mu.Lock()
+  defer e.mu.Unlock()
+
+  return ModelStatus{Current: e.current.Load(), LastError: e.lastError, LastErrorAt: e.lastErrorAt}, true
+}
+
+// The names of all models, sorted
+func (r *Registry) Names() []string {
+  r.mu.RLock()
+  defer r.mu.RUnlock()
+
+  names := make([]string, 0, len(r.entries))
+  for k := range r.entries {
+    names = append(names, k)
+  }
+
+  sort.Strings(names)
+  return names
+}
+
+// Reloads one model if its file changed, and reports whether a new version
+// was swapped in. A file that was only touched keeps its version.
+func (r *Registry) Reload(name string) (bool, error) {
+  e, found := r.entry(name)
+  if !found {
+    return false, fmt.Errorf("punkt: no model named %q in the registry", name)
+  }
+
+  return r.reload(name, e)
+}
+
+func (r *Registry) reload(name string, e *registryEntry) (swapped bool, err error) {
+  e.mu.Lock()
+  defer e.mu.Unlock()
+
+  info, err := os.Stat(e.path)
+  if err != nil {
+    e.lastError, e.lastErrorAt = err, time.Now()
+    return false, err
+  }
+
+  current := e.current.Load()
+  if current != nil && info.ModTime().Equal(e.modTime) && info.Size() == e.size {
+    return false, nil
+  }
+
+  // a broken file is reported once, not on every poll until it is fixed
+  if e.lastError != nil && info.ModTime().Equal(e.failedModTime) && info.Size() == e.failedSize {
+    return false, nil
+  }
+
+  defer func() {
+    if err != nil {
+      e.lastError, e.lastErrorAt = err, time.Now()
+      e.failedModTime, e.failedSize = info.ModTime(), info.Size()
+    }
+  }()
+
+  contents, err := ioutil.ReadFile(e.path)
+  if err != nil {
+    return false, err
+  }
+
+  sum := sha256.Sum256(contents)
+  version := hex.EncodeToString(sum[:])
+
+  if current != nil && version == current.Version {
+    e.modTime, e.size = info.ModTime(), info.Size()
+    return false, nil
+  }
+
+  p, err := loadParametersFromContents(e.path, contents)
+  if err != nil {
+    return false, err
+  }
+
+  if r.Check != nil {
+    if err := r.Check(name, p); err != nil {
+      return false, fmt.Errorf("%s: %v", e.path, err)
+    }
+  }
+
+  e.current.Store(&LoadedModel{
+    Name: name,
+    Path: e.path,
+    Parameters: p,
+    Version: version,
+    ModTime: info.ModTime(),
+    LoadedAt: time.Now(),
+  })
+
+  e.modTime, e.size = info.ModTime(), info.Size()
+  e.lastError, e.lastErrorAt = nil, time.Time{}
+  return true, nil
+}
+
+// Reloads every model whose file changed, and returns the errors by name
+func (r *Registry) Poll() map[string]error {
+  var errs map[string]error
+
+  for _, name := range r.Names() {
+    if _, err := r.Reload(name); err != nil {
+      if errs == nil {
+        errs = map[string]error{}
+      }
+      errs[name] = err
+    }
+  }
+
+  return errs
+}
+
+// Polls every interval until the context is done. Run it in its own goroutine.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+  ticker := time.NewTicker(interval)
+  defer ticker.Stop()
+
+  for {
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+      for name, err := range r.Poll() {
+        if r.OnError != nil {
+          r.OnError(name, err)
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,219 @@
+package punkt
+
+import (
+  "encoding/csv"
+  "encoding/json"
+  "fmt"
+  "io"
+  "math"
+  "sort"
+  "strconv"
+)
+
+// The kinds of candidates in a TrainingReport, named like the model fields
+type CandidateKind string
+
+const (
+  CANDIDATE_ABBREV_TYPE CandidateKind = "abbrev_type"
+  CANDIDATE_COLLOCATION CandidateKind = "collocation"
+  CANDIDATE_SENTENCE_STARTER CandidateKind = "sentence_starter"
+)
+
+// Why a candidate was accepted or rejected
+const (
+  REASON_SCORE = "score"               // the score reached the cutoff
+  REASON_BELOW_CUTOFF = "below_cutoff" // the score did not
+  REASON_RARE_ABBREV = "rare_abbrev"   // a rare type followed by lower case or , ; :
+  REASON_GOLD = "gold"                 // seen with a period mostly inside gold sentences
+  REASON_MIN_FREQ = "min_freq"         // seen too few times to be scored
+  REASON_RATIO = "ratio"               // reached the cutoff, but is too common on its own
+  REASON_STARTER = "starter"           // the second type is a sentence starter
+  REASON_SEED = "seed"                 // given in Trainer.Seeds
+  REASON_FORBIDDEN = "forbidden"       // a non-abbreviation in Trainer.Seeds
+)
+
+// One type (or pair, for collocations) the trainer considered. Count is how
+// often it was seen as a candidate: with a final period for abbreviations,
+// after a sentence break for sentence starters and as a pair for
+// collocations. TypeCount and Type2Count count the types with and without a
+// final period.
+type TrainingCandidate struct {
+  Kind CandidateKind
+  Type string
+  Type2 string
+  Count int
+  TypeCount int
+  Type2Count int
+  Score float64
+  Cutoff float64
+  Accepted bool
+  Reason string
+}
+
+// Whether the decision came from Trainer.Seeds rather than the statistics
+func (c TrainingCandidate) Seeded() bool {
+  return c.Reason == REASON_SEED || c.Reason == REASON_FORBIDDEN
+}
+
+// How far the score is above the cutoff, negative if below
+func (c TrainingCandidate) Margin() float64 {
+  return c.Score - c.Cutoff
+}
+
+type jsonCandidate struct {
+  Kind CandidateKind `json:"kind"`
+  Type string `json:"type"`
+  Type2 string `json:"type2,omitempty"`
+  Count int `json:"count"`
+  TypeCount int `json:"type_count"`
+  Type2Count int `json:"type2_count,omitempty"`
+  Score *float64 `json:"score"` // null if not finite
+  Cutoff float64 `json:"cutoff"`
+  Accepted bool `json:"accepted"`
+  Reason string `json:"reason"`
+}
+
+func (c TrainingCandidate) MarshalJSON() ([]byte, error) {
+  j := jsonCandidate{c.Kind, c.Type, c.Type2, c.Count, c.TypeCount, c.Type2Count, nil, c.Cutoff, c.Accepted, c.Reason}
+
+  if !math.IsNaN(c.Score) && !math.IsInf(c.Score, 0) {
+    j.Score = &c.Score
+  }
+
+  return json.Marshal(j)
+}
+
+func (c *TrainingCandidate) UnmarshalJSON(b []byte) error {
+  var j jsonCandidate
+  if err := json.Unmarshal(b, &j); err != nil {
+    return err
+  }
+
+  *c = TrainingCandidate{j.Kind, j.Type, j.Type2, j.Count, j.TypeCount, j.Type2Count, math.NaN(), j.Cutoff, j.Accepted, j.Reason}
+  if j.Score != nil {
+    c.Score = *j.Score
+  }
+
+  return nil
+}
+
+// Every candidate scored by the last training run, from Trainer.Report
+type TrainingReport struct {
+  TokenCount int `json:"token_count"`
+  PeriodTokensCount int `json:"period_tokens_count"`
+  SentenceBreakCount int `json:"sentence_break_count"`
+  Candidates []TrainingCandidate `json:"candidates"`
+}
+
+// The candidate of the given kind, with type2 only set for collocations
+func (r TrainingReport) Find(kind CandidateKind, type1, type2 string) (TrainingCandidate, bool) {
+  for _, c := range r.Candidates {
+    if c.Kind == kind && c.Type == type1 && c.Type2 == type2 {
+      return c, true
+    }
+  }
+
+  return TrainingCandidate{}, false
+}
+
+// The candidates of one kind
+func (r TrainingReport) OfKind(kind CandidateKind) (out []TrainingCandidate) {
+  for _, c := range r.Candidates {
+    if c.Kind == kind {
+      out = append(out, c)
+    }
+  }
+
+  return
+}
+
+var candidateOrders = map[string]func(a, b TrainingCandidate) bool{
+  "kind": func(a, b TrainingCandidate) bool { return false },
+  "type": func(a, b TrainingCandidate) bool { return a.Type < b.Type },
+  "count": func(a, b TrainingCandidate) bool { return a.Count > b.Count },
+  "score": func(a, b TrainingCandidate) bool { return a.Score > b.Score },
+  "margin": func(a, b TrainingCandidate) bool { return math.Abs(a.Margin()) < math.Abs(b.Margin()) },
+}
+
+// Sorts the candidates by kind and then by key: "type", "count" (highest
+// first), "score" (highest first) or "margin" (closest to the cutoff
+// first). The key "kind" sorts by kind and type only.
+func (r *TrainingReport) Sort(key string) error {
+  less, found := candidateOrders[key]
+  if !found {
+    return fmt.Errorf("punkt: can't sort a training report by %q", key)
+  }
+
+  sort.SliceStable(r.Candidates, func(i, j int) bool {
+    a, b := r.Candidates[i], r.Candidates[j]
+
+    switch {
+    case a.Kind != b.Kind:
+      return a.Kind < b.Kind
+    case less(a, b):
+      return true
+    case less(b, a):
+      return false
+    case a.Type != b.Type:
+      return a.Type < b.Type
+    default:
+      return a.Type2 < b.Type2
+    }
+  })
+
+  return nil
+}
+
+var trainingReportColumns = []string{"kind", "type", "type2", "count", "type_count", "type2_count", "score", "cutoff", "accepted", "reason"}
+
+// Writes the candidates as CSV with a header row
+func (r TrainingReport) WriteCSV(w io.Writer) error {
+  out := csv.NewWriter(w)
+  out.Write(trainingReportColumns)
+
+  for _, c := range r.Candidates {
+    out.Write([]string{
+      string(c.Kind),
+      c.Type,
+      c.Type2,
+      strconv.Itoa(c.Count),
+      strconv.Itoa(c.TypeCount),
+      strconv.Itoa(c.Type2Count),
+      strconv.FormatFloat(c.Score, 'g', -1, 64),
+      strconv.FormatFloat(c.Cutoff, 'g', -1, 64),
+      strconv.FormatBool(c.Accepted),
+      c.Reason,
+    })
+  }
+
+  out.Flush()
+  return out.Error()
+}
+
+func (r TrainingReport) WriteJSON(w io.Writer) error {
+  b, err := json.MarshalIndent(r, "", "  ")
+  if err != nil {
+    return err
+  }
+
+  _, err = w.Write(append(b, '\n'))
+  return err
+}
+
+func (r TrainingReport) String() string {
+  accepted := map[CandidateKind]int{}
+  total := map[CandidateKind]int{}
+
+  for _, c := range r.Candidates {
+    total[c.Kind]++
+    if c.Accepted {
+      accepted[c.Kind]++
+    }
+  }
+
+  return fmt.Sprintf("%d tokens, %d sentence breaks: %d of %d abbreviation candidates, %d of %d sentence starters and %d of %d collocations accepted",
+    r.TokenCount, r.SentenceBreakCount,
+    accepted[CANDIDATE_ABBREV_TYPE], total[CANDIDATE_ABBREV_TYPE],
+    accepted[CANDIDATE_SENTENCE_STARTER], total[CANDIDATE_SENTENCE_STARTER],
+    accepted[CANDIDATE_COLLOCATION], total[CANDIDATE_COLLOCATION])
+}
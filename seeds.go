@@ -0,0 +1,152 @@
+package punkt
+
+// Helpers for Trainer.Seeds, which may be nil
+
+// forbidden abbreviations win, as in an Overlay
+func (t *Trainer) seededAbbrevType(s string) bool {
+  return t.Seeds != nil && t.Seeds.AbbrevTypes[s] && !t.Seeds.NonAbbrevTypes[s]
+}
+
+func (t *Trainer) forbiddenAbbrevType(s string) bool {
+  return t.Seeds != nil && t.Seeds.NonAbbrevTypes[s]
+}
+
+func (t *Trainer) seededSentenceStarter(s string) bool {
+  return t.Seeds != nil && t.Seeds.SentenceStarters[s]
+}
+
+func (t *Trainer) seededCollocation(c Collocation) bool {
+  return t.Seeds != nil && t.Seeds.Collocations[c]
+}
+
+// Adds the forced abbreviations before anything is learned, so the first
+// pass and the statistics built on it already treat them as abbreviations
+func (t *Trainer) seedAbbrevTypes(parameters *LanguageParameters) {
+  if t.Seeds == nil {
+    return
+  }
+
+  for k := range t.Seeds.AbbrevTypes {
+    if t.seededAbbrevType(k) {
+      parameters.SaveAbbrevType(k)
+    }
+  }
+}
+
+// Overrides the learned decision on a candidate where the seeds say otherwise
+func (t *Trainer) applySeeds(c *TrainingCandidate) {
+  switch c.Kind {
+  case CANDIDATE_ABBREV_TYPE:
+    if t.seededAbbrevType(c.Type) {
+      c.Accepted, c.Reason = true, REASON_SEED
+    } else if t.forbiddenAbbrevType(c.Type) {
+      c.Accepted, c.Reason = false, REASON_FORBIDDEN
+    }
+  case CANDIDATE_SENTENCE_STARTER:
+    if t.seededSentenceStarter(c.Type) {
+      c.Accepted, c.Reason = true, REASON_SEED
+    }
+  case CANDIDATE_COLLOCATION:
+    if t.seededCollocation(Collocation{c.Type, c.Type2}) {
+      c.Accepted, c.Reason = true, REASON_SEED
+    }
+  }
+}
+
+func (t *Trainer) abbrevCandidate(tType string, score, cutoff float64) TrainingCandidate {
+  return TrainingCandidate{
+    Kind: CANDIDATE_ABBREV_TYPE,
+    Type: tType,
+    Count: t.TypeFdist.Get(tType + "."),
+    TypeCount: t.typeCount(tType),
+    Score: score,
+    Cutoff: cutoff,
+  }
+}
+
+// Adds the candidate to the report, or replaces the one for the same type
+func (t *Trainer) addAbbrevCandidate(index map[string]int, c TrainingCandidate) {
+  if i, found := index[c.Type]; found {
+    t.report.Candidates[i] = c
+    return
+  }
+
+  index[c.Type] = len(t.report.Candidates)
+  t.report.Candidates = append(t.report.Candidates, c)
+}
+
+// Reports the forced abbreviations that were never scored
+func (t *Trainer) addSeededAbbrevCandidates(index map[string]int) {
+  if t.Seeds == nil {
+    return
+  }
+
+  for _, k := range sortedKeys(t.Seeds.AbbrevTypes) {
+    if _, found := index[k]; !found && t.seededAbbrevType(k) {
+      c := t.abbrevCandidate(k, 0, t.Settings().AbbrevCutoff)
+      c.Accepted, c.Reason = true, REASON_SEED
+      t.addAbbrevCandidate(index, c)
+    }
+  }
+}
+
+// Applies the seeds to the scored sentence starters, and adds the seeded
+// ones that were never scored
+func (t *Trainer) seedSentenceStarters(scored []TrainingCandidate) []TrainingCandidate {
+  seen := map[string]bool{}
+
+  for i := range scored {
+    t.applySeeds(&scored[i])
+    seen[scored[i].Type] = true
+  }
+
+  if t.Seeds != nil {
+    for _, k := range sortedKeys(t.Seeds.SentenceStarters) {
+      if !seen[k] {
+        scored = append(scored, TrainingCandidate{
+          Kind: CANDIDATE_SENTENCE_STARTER,
+          Type: k,
+          Count: t.SentenceStarterFdist.Get(k),
+          TypeCount: t.typeCount(k),
+          Score: t.SentenceStarterScore(k),
+          Cutoff: t.Settings().SentStarterCutoff,
+          Accepted: true,
+          Reason: REASON_SEED,
+        })
+      }
+    }
+  }
+
+  return scored
+}
+
+// The same for collocations
+func (t *Trainer) seedCollocations(scored []TrainingCandidate) []TrainingCandidate {
+  seen := map[Collocation]bool{}
+
+  for i := range scored {
+    t.applySeeds(&scored[i])
+    seen[Collocation{scored[i].Type, scored[i].Type2}] = true
+  }
+
+  if t.Seeds != nil {
+    for _, k := range sortedCollocations(t.Seeds.Collocations) {
+      if !seen[k] {
+        scored = append(scored, TrainingCandidate{
+          Kind: CANDIDATE_COLLOCATION,
+          Type: k.Type1,
+          Type2: k.Type2,
+          Count: t.CollocationFdist.Get(k),
+          TypeCount: t.typeCount(k.Type1),
+          Type2Count: t.typeCount(k.Type2),
+          Score: t.CollocationScore(k.Type1, k.Type2),
+          Cutoff: t.Settings().CollocationCutoff,
+          Accepted: true,
+          Reason: REASON_SEED,
+        })
+      }
+    }
+  }
+
+  return scored
+}
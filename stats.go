@@ -0,0 +1,172 @@
+package punkt
+
+import (
+  "fmt"
+  "sort"
+  "strings"
+  "unicode/utf8"
+)
+
+// Counts describing a model, from LanguageParameters.Stats
+type ParameterStats struct {
+  AbbrevTypes int
+  Collocations int
+  SentenceStarters int
+  OrthoTypes int
+
+  // how many types have each combination of ortho context flags, most
+  // common first
+  OrthoFlags []SampleCount[OrthoContext]
+
+  // AbbrevLengths[n] is the number of abbreviations n runes long
+  AbbrevLengths []int
+
+  // abbreviations like "u.s" that have periods inside
+  AbbrevsWithPeriods int
+}
+
+func (p LanguageParameters) Stats() (s ParameterStats) {
+  s.AbbrevTypes = len(p.AbbrevTypes)
+  s.Collocations = len(p.Collocations)
+  s.SentenceStarters = len(p.SentenceStarters)
+  s.OrthoTypes = len(p.OrthographicContext)
+
+  var flags FrequencyDistribution[OrthoContext]
+  for _, v := range p.OrthographicContext {
+    flags.Inc(v)
+  }
+
+  s.OrthoFlags = append(s.OrthoFlags, flags.OrderedSamples()...)
+  sort.SliceStable(s.OrthoFlags, func(i, j int) bool {
+    if s.OrthoFlags[i].Count != s.OrthoFlags[j].Count {
+      return s.OrthoFlags[i].Count > s.OrthoFlags[j].Count
+    }
+
+    return s.OrthoFlags[i].Sample < s.OrthoFlags[j].Sample
+  })
+
+  for k := range p.AbbrevTypes {
+    n := utf8.RuneCountInString(k)
+    for len(s.AbbrevLengths) <= n {
+      s.AbbrevLengths = append(s.AbbrevLengths, 0)
+    }
+    s.AbbrevLengths[n]++
+
+    if strings.Contains(k, ".") {
+      s.AbbrevsWithPeriods++
+    }
+  }
+
+  return
+}
+
+func (s ParameterStats) String() (out string) {
+  out += fmt.Sprintf("abbrev_types: %d (%d with periods)\n", s.AbbrevTypes, s.AbbrevsWithPeriods)
+  out += fmt.Sprintf("collocations: %d\n", s.Collocations)
+  out += fmt.Sprintf("sentence_starters: %d\n", s.SentenceStarters)
+  out += fmt.Sprintf("ortho_context: %d\n", s.OrthoTypes)
+
+  for _, f := range s.OrthoFlags {
+    out += fmt.Sprintf("  %-30v %d\n", f.Sample, f.Count)
+  }
+
+  out += "abbreviation lengths:\n"
+  for n, count := range s.AbbrevLengths {
+    if count > 0 {
+      out += fmt.Sprintf("  %3d %d\n", n, count)
+    }
+  }
+
+  return
+}
+
+// How much of a sample text a model has orthographic context for. Only word
+// types with cased letters are counted, since the others never get any.
+type CoverageReport struct {
+  Types int
+  CoveredTypes int
+  Tokens int
+  CoveredTokens int
+
+  // the most frequent types without ortho context, at most MAX_UNCOVERED_TYPES
+  Uncovered []SampleCount[string]
+}
+
+const MAX_UNCOVERED_TYPES = 20
+
+func (r CoverageReport) TypeCoverage() float64 {
+  if r.Types == 0 {
+    return 0
+  }
+
+  return float64(r.CoveredTypes) / float64(r.Types)
+}
+
+func (r CoverageReport) TokenCoverage() float64 {
+  if r.Tokens == 0 {
+    return 0
+  }
+
+  return float64(r.CoveredTokens) / float64(r.Tokens)
+}
+
+func (r CoverageReport) String() string {
+  return fmt.Sprintf("ortho context covers %d of %d types (%.1f%%) and %d of %d tokens (%.1f%%)",
+    r.CoveredTypes, r.Types, 100 * r.TypeCoverage(), r.CoveredTokens, r.Tokens, 100 * r.TokenCoverage())
+}
+
+// Reports which of the word types in text the parameters have orthographic
+// context for. The text is annotated with the first pass, and types are
+// looked up as the second pass does: without the period of a sentence break,
+// but with the period of an abbreviation.
+func Coverage(p ParameterSet, text string) (r CoverageReport) {
+  var uncovered FrequencyDistribution[string]
+  seen := map[string]bool{}
+
+  words := SplitTextIntoWords(text)
+  tokens := make([]*Token, len(words))
+  for i, word := range words {
+    tokens[i] = MakeToken(word)
+  }
+
+  for _, tok := range AnnotateFirstPass(p, tokens) {
+    tType := tok.TypeWithoutSentencePeriod()
+    if !hasCasedLetter(tType) {
+      continue
+    }
+
+    covered := p.GetOrthographicContext(tType) != 0
+
+    r.Tokens++
+    if covered {
+      r.CoveredTokens++
+    } else {
+      uncovered.Inc(tType)
+    }
+
+    if !seen[tType] {
+      seen[tType] = true
+      r.Types++
+
+      if covered {
+        r.CoveredTypes++
+      }
+    }
+  }
+
+  samples := append([]SampleCount[string](nil), uncovered.OrderedSamples()...)
+  sort.SliceStable(samples, func(i, j int) bool {
+    if samples[i].Count != samples[j].Count {
+      return samples[i].Count > samples[j].Count
+    }
+
+    return samples[i].Sample < samples[j].Sample
+  })
+
+  if len(samples) > MAX_UNCOVERED_TYPES {
+    samples = samples[:MAX_UNCOVERED_TYPES]
+  }
+
+  r.Uncovered = samples
+  return
+}
@@ -0,0 +1,166 @@
+package punkt
+
+import (
+  "context"
+  "strings"
+)
+
+// the share of the times a type is seen with a final period in gold
+// sentences that must be inside a sentence, not at its end, for it to be
+// labeled an abbreviation. A single badly split sentence then doesn't make
+// an abbreviation of a word that usually ends sentences.
+const GOLD_ABBREV_CUTOFF = 0.5
+
+// Trains from text that is already split into sentences, one per line.
+// Blank lines are skipped.
+func (t *Trainer) TrainWithSegmentedText(text string) *LanguageParameters {
+  return t.TrainWithSentences(strings.Split(text, "\n"))
+}
+
+// Trains from sentences known to be correct, instead of guessing the
+// boundaries. Types seen with a final period mostly inside sentences rather
+// than at their end are labeled abbreviations, sentence starters and collocations are counted only at the
+// real boundaries (and inside sentences), and orthographic contexts are
+// learned as sentence initial or internal, never unknown. The counts add to
+// the Trainer's statistics like unsupervised training.
+func (t *Trainer) TrainWithSentences(sentences []string) *LanguageParameters {
+  params, _ := t.TrainWithSentencesContext(context.Background(), sentences, nil)
+  return params
+}
+
+// Like TrainWithSentences, with cancellation and progress as for
+// TrainWithTextContext. Progress in the counting phase is in sentences.
+func (t *Trainer) TrainWithSentencesContext(ctx context.Context, sentences []string, progress func(TrainingProgress)) (*LanguageParameters, error) {
+  return t.runTraining(ctx, progress, func(r *trainingRun) (*LanguageParameters, error) {
+    return t.trainFromSentences(r, sentences)
+  })
+}
+
+func (t *Trainer) trainFromSentences(r *trainingRun, sentences []string) (*LanguageParameters, error) {
+  parameters := new(LanguageParameters)
+  r.use(parameters)
+
+  if err := r.start(PHASE_COUNTING, len(sentences)); err != nil {
+    return nil, err
+  }
+
+  for n, sentence := range sentences {
+    if err := r.step(n); err != nil {
+      return nil, err
+    }
+
+    words := SplitTextIntoWords(strings.TrimSpace(sentence))
+    if len(words) == 0 {
+      continue
+    }
+
+    tokens := make([]*Token, len(words))
+    for i := range words {
+      tokens[i] = MakeToken(words[i])
+    }
+
+    last := len(tokens) - 1
+
+    // a period before closing punctuation still ends the sentence
+    lastWord := last
+    for lastWord > 0 && !tokens[lastWord].MatchNonPunctuation() {
+      lastWord--
+    }
+
+    if tok := tokens[lastWord]; tok.EndsWithPeriod() {
+      tok.SetSentenceBreak(true)
+
+      if !tok.MatchEllipsis() && tok.MatchNonPunctuation() {
+        t.GoldFinalFdist.Inc(tok.TypeWithoutPeriod())
+      }
+    } else {
+      tokens[last].SetSentenceBreak(true)
+    }
+    t.SentenceBreakCount += 1
+
+    for i, tok := range tokens {
+      t.TypeFdist.Inc(tok.Type)
+
+      if tok.EndsWithPeriod() {
+        t.PeriodTokensCount += 1
+
+        if i < lastWord && !tok.MatchEllipsis() && tok.MatchNonPunctuation() {
+          tok.SetAbbr(true)
+          t.GoldAbbrevFdist.Inc(tok.TypeWithoutPeriod())
+        }
+      }
+
+      context := "internal"
+      if i == 0 {
+        context = "initial"
+
+        if tok.MatchAlpha() {
+          t.SentenceStarterFdist.Inc(tok.Type)
+        }
+      } else if prev := tokens[i-1]; prev.EndsWithPeriod() && prev.MatchNonPunctuation() && tok.MatchNonPunctuation() {
+        t.CollocationFdist.Inc(Collocation{prev.TypeWithoutPeriod(), tok.TypeWithoutPeriod()})
+      }
+
+      if flag := orthoFlag(context, tok); flag > 0 {
+        parameters.AddOrthographicContext(tok.TypeWithoutSentencePeriod(), flag)
+      }
+    }
+  }
+
+  if err := r.finish(); err != nil {
+    return nil, err
+  }
+
+  t.report = TrainingReport{}
+  abbrevCandidates := map[string]int{}
+  t.seedAbbrevTypes(parameters)
+  samples := t.GoldAbbrevFdist.OrderedSamples()
+
+  if err := r.start(PHASE_ABBREVIATIONS, len(samples)); err != nil {
+    return nil, err
+  }
+
+  for i, cs := range samples {
+    if err := r.step(i); err != nil {
+      return nil, err
+    }
+
+    // the share of the occurrences with a final period inside sentences
+    score := float64(cs.Count) / float64(cs.Count + t.GoldFinalFdist.Get(cs.Sample))
+
+    c := TrainingCandidate{
+      Kind: CANDIDATE_ABBREV_TYPE,
+      Type: cs.Sample,
+      Count: cs.Count,
+      TypeCount: t.typeCount(cs.Sample),
+      Score: score,
+      Cutoff: GOLD_ABBREV_CUTOFF,
+      Accepted: score >= GOLD_ABBREV_CUTOFF,
+      Reason: REASON_GOLD,
+    }
+
+    if !c.Accepted {
+      c.Reason = REASON_BELOW_CUTOFF
+    }
+
+    t.applySeeds(&c)
+
+    if c.Accepted {
+      parameters.SaveAbbrevType(cs.Sample)
+    }
+
+    t.addAbbrevCandidate(abbrevCandidates, c)
+  }
+
+  if err := r.finish(); err != nil {
+    return nil, err
+  }
+
+  t.addSeededAbbrevCandidates(abbrevCandidates)
+  if err := t.finalizeTraining(r, parameters); err != nil {
+    return nil, err
+  }
+
+  parameters.Metadata = t.trainingMetadata()
+  return parameters, nil
+}
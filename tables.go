@@ -0,0 +1,69 @@
+package punkt
+
+import (
+  "sort"
+)
+
+// A model as sorted tables instead of maps. This is the form written by
+// GenerateGoSource, and converts back into LanguageParameters without
+// parsing anything. OrthoTypes and OrthoFlags are parallel slices.
+type ParameterTables struct {
+  AbbrevTypes []string
+  Collocations [][2]string
+  SentenceStarters []string
+  OrthoTypes []string
+  OrthoFlags []OrthoContext
+  Metadata ModelMetadata
+}
+
+func (p LanguageParameters) Tables() (t ParameterTables) {
+  t.AbbrevTypes = sortedKeys(p.AbbrevTypes)
+  t.SentenceStarters = sortedKeys(p.SentenceStarters)
+
+  for _, k := range sortedCollocations(p.Collocations) {
+    t.Collocations = append(t.Collocations, [2]string{k.Type1, k.Type2})
+  }
+
+  t.OrthoTypes = make([]string, 0, len(p.OrthographicContext))
+  for k := range p.OrthographicContext {
+    t.OrthoTypes = append(t.OrthoTypes, k)
+  }
+  sort.Strings(t.OrthoTypes)
+
+  t.OrthoFlags = make([]OrthoContext, len(t.OrthoTypes))
+  for i, k := range t.OrthoTypes {
+    t.OrthoFlags[i] = p.OrthographicContext[k]
+  }
+
+  t.Metadata = p.Metadata
+  return
+}
+
+// Builds new LanguageParameters from the tables
+func (t ParameterTables) Parameters() *LanguageParameters {
+  p := &LanguageParameters{
+    AbbrevTypes: make(map[string]bool, len(t.AbbrevTypes)),
+    Collocations: make(map[Collocation]bool, len(t.Collocations)),
+    SentenceStarters: make(map[string]bool, len(t.SentenceStarters)),
+    OrthographicContext: make(map[string]OrthoContext, len(t.OrthoTypes)),
+    Metadata: t.Metadata,
+  }
+
+  for _, v := range t.AbbrevTypes {
+    p.AbbrevTypes[v] = true
+  }
+
+  for _, v := range t.Collocations {
+    p.SaveCollocation(v[0], v[1])
+  }
+
+  for _, v := range t.SentenceStarters {
+    p.SentenceStarters[v] = true
+  }
+
+  for i, v := range t.OrthoTypes {
+    p.OrthographicContext[v] = t.OrthoFlags[i]
+  }
+
+  return p
+}
@@ -0,0 +1,106 @@
+package punkt
+
+import (
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type AbbrevPacksSuite struct{}
+
+var abbrevPacksSuite = Suite(&AbbrevPacksSuite{})
+
+// sentences the bundled newswire models split wrongly without a pack, at least
+// one for every pack
+var packRegressions = []struct {
+  Language string
+  Domain string
+  Text string
+  Sentences []string
+}{
+  {"english", "legal", "The holding in Smith v. Jones was narrow. See 42 U.S.C. Sec. 1983 for details.",
+    []string{"The holding in Smith v. Jones was narrow.", "See 42 U.S.C. Sec. 1983 for details."}},
+  {"english", "legal", "The Second Cir. affirmed the ruling. The case ended.",
+    []string{"The Second Cir. affirmed the ruling.", "The case ended."}},
+  {"english", "biomedical", "Patients received the drug i.v. and were monitored. It worked.",
+    []string{"Patients received the drug i.v. and were monitored.", "It worked."}},
+  {"english", "biomedical", "The effect was seen at approx. four hours. It faded later.",
+    []string{"The effect was seen at approx. four hours.", "It faded later."}},
+  {"english", "financial", "Acme Holdings Plc. announced results. Shares rose.",
+    []string{"Acme Holdings Plc. announced results.", "Shares rose."}},
+  {"english", "financial", "Revenue was approx. $5 bn for the year. Margins improved.",
+    []string{"Revenue was approx. $5 bn for the year.", "Margins improved."}},
+  {"english", "academic", "Compare the results (cf. Table 2). The trend is clear.",
+    []string{"Compare the results (cf. Table 2).", "The trend is clear."}},
+  {"english", "academic", "As shown by Smith et al. in an earlier study, the effect vanished. This is expected.",
+    []string{"As shown by Smith et al. in an earlier study, the effect vanished.", "This is expected."}},
+  {"german", "legal", "Dies folgt aus Art. 3 des Gesetzes. Der Rest ist klar.",
+    []string{"Dies folgt aus Art. 3 des Gesetzes.", "Der Rest ist klar."}},
+  {"german", "financial", "Der Umsatz lag bei 3 Tsd. Euro im Monat. Der Rest folgt.",
+    []string{"Der Umsatz lag bei 3 Tsd. Euro im Monat.", "Der Rest folgt."}},
+  {"german", "academic", "Das steht auf S. 12 im Buch. Der Rest folgt.",
+    []string{"Das steht auf S. 12 im Buch.", "Der Rest folgt."}},
+  {"french", "legal", "Selon l'art. 12 du code, la règle s'applique. Le reste suit.",
+    []string{"Selon l'art. 12 du code, la règle s'applique.", "Le reste suit."}},
+  {"french", "academic", "Voir le chap. 4 du livre. Le reste suit.",
+    []string{"Voir le chap. 4 du livre.", "Le reste suit."}},
+  {"spanish", "legal", "Según el art. 14 de la Constitución, todos son iguales. El resto sigue.",
+    []string{"Según el art. 14 de la Constitución, todos son iguales.", "El resto sigue."}},
+  {"spanish", "academic", "Véase el cap. 3 del libro. El resto sigue.",
+    []string{"Véase el cap. 3 del libro.", "El resto sigue."}},
+}
+
+func (s *AbbrevPacksSuite) TestRegressions(c *C) {
+  covered := map[string]bool{}
+
+  for _, r := range packRegressions {
+    t := new(Tokenizer)
+    c.Assert(t.SetLanguage(r.Language), IsNil)
+    c.Check(t.SentencesFromText(r.Text), Not(DeepEquals), r.Sentences, Commentf("%s/%s is already fixed without the pack", r.Language, r.Domain))
+
+    c.Assert(t.EnableAbbrevPack(r.Domain), IsNil)
+    c.Check(t.SentencesFromText(r.Text), DeepEquals, r.Sentences, Commentf("%s/%s", r.Language, r.Domain))
+    covered[r.Language + "/" + r.Domain] = true
+  }
+
+  for _, lang := range []string{"english", "german", "french", "spanish"} {
+    for _, p := range AbbrevPacks(lang) {
+      c.Check(covered[lang + "/" + p.Domain], Equals, true, Commentf("no regression for %v", p))
+    }
+  }
+}
+
+func (s *AbbrevPacksSuite) TestListing(c *C) {
+  packs := AbbrevPacks("english")
+  domains := make([]string, len(packs))
+  for i, p := range packs {
+    domains[i] = p.Domain
+  }
+
+  c.Check(domains, DeepEquals, []string{"academic", "biomedical", "financial", "legal"})
+
+  p, found := FindAbbrevPack("legal", "english")
+  c.Assert(found, Equals, true)
+  c.Check(p.AbbrevTypes, Not(HasLen), 0)
+
+  _, found = FindAbbrevPack("legal", "klingon")
+  c.Check(found, Equals, false)
+}
+
+func (s *AbbrevPacksSuite) TestEntriesAreNormalized(c *C) {
+  for _, lang := range []string{"english", "german", "french", "spanish"} {
+    for _, p := range AbbrevPacks(lang) {
+      o := NewOverlay(nil)
+      p.Apply(o)
+      c.Check(len(o.AbbrevTypes), Equals, len(p.AbbrevTypes), Commentf("duplicate or unnormalized entry in %v", p))
+      for _, a := range p.AbbrevTypes {
+        c.Check(o.AbbrevTypes[a], Equals, true, Commentf("%q in %v", a, p))
+      }
+    }
+  }
+}
+
+func (s *AbbrevPacksSuite) TestUnknownPack(c *C) {
+  t := new(Tokenizer)
+  c.Assert(t.SetLanguage("english"), IsNil)
+  c.Check(t.EnableAbbrevPack("astrology"), ErrorMatches, "punkt: no \"astrology\" abbreviation pack for language \"english\"")
+}
@@ -72,3 +72,31 @@ func (s *AnnotateSuite) TestTokenizer(c *C) {
 
   fmt.Printf("%#v\n", t.SentencesFromText(str))
 }
+
+// clearing a break that was never set used to set it, so a number before a
+// comma ended a sentence
+func (s *AnnotateSuite) TestNumberBeforeComma(c *C) {
+  tokens := AnnotateTokens(s.parameters, []*Token{MakeToken("15"), MakeToken(","), MakeToken("a")})
+  c.Check(tokens[0].IsSentenceBreak(), Equals, false)
+
+  t := new(Tokenizer)
+  t.SetLanguage("english")
+
+  c.Check(t.SentencesFromText("The meeting moved to Jan. 15, a Monday. Nobody came."), DeepEquals,
+    []string{"The meeting moved to Jan. 15, a Monday.", "Nobody came."})
+  c.Check(t.SentencesFromText("It opened on Dec. 3, 1999. The\ncrowd was huge."), DeepEquals,
+    []string{"It opened on Dec. 3, 1999.", "The\ncrowd was huge."})
+}
+
+func (s *AnnotateSuite) TestParagraphStarts(c *C) {
+  tokens := TokenizeText("One here.\nTwo here.\n\nThree here.")
+
+  var starts []bool
+  for _, tok := range tokens {
+    if tok.IsLineStart() {
+      starts = append(starts, tok.IsParagraphStart())
+    }
+  }
+
+  c.Check(starts, DeepEquals, []bool{false, false, true})
+}
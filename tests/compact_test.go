@@ -0,0 +1,167 @@
+package punkt
+
+import (
+  "runtime"
+  "strings"
+  "testing"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type CompactSuite struct{}
+
+var compactSuite = Suite(&CompactSuite{})
+
+func (s *CompactSuite) TestEmpty(c *C) {
+  p := new(LanguageParameters).Compact()
+
+  c.Check(p.HasAbbrevType("dr"), Equals, false)
+  c.Check(p.HasCollocation("a", "b"), Equals, false)
+  c.Check(p.GetOrthographicContext("dog"), Equals, OrthoContext(0))
+}
+
+func (s *CompactSuite) TestSameAnswers(c *C) {
+  for _, lang := range LANGUAGES {
+    p := LoadLanguage(lang)
+    cp := p.Compact()
+
+    for k := range p.AbbrevTypes {
+      c.Assert(cp.HasAbbrevType(k), Equals, true, Commentf("%s abbrev %q", lang, k))
+      c.Assert(cp.HasAbbrevType(k + "x"), Equals, p.HasAbbrevType(k + "x"))
+    }
+
+    for k := range p.SentenceStarters {
+      c.Assert(cp.HasSentenceStarter(k), Equals, true, Commentf("%s starter %q", lang, k))
+      c.Assert(cp.HasAbbrevType(k), Equals, p.HasAbbrevType(k))
+    }
+
+    for _, pair := range p.Tables().Collocations {
+      c.Assert(cp.HasCollocation(pair[0], pair[1]), Equals, true, Commentf("%s collocation %q", lang, pair))
+      c.Assert(cp.HasCollocation(pair[1], pair[0]), Equals, p.HasCollocation(pair[1], pair[0]))
+    }
+
+    for k, v := range p.OrthographicContext {
+      c.Assert(cp.GetOrthographicContext(k), Equals, v, Commentf("%s ortho %q", lang, k))
+      c.Assert(cp.HasSentenceStarter(k), Equals, p.HasSentenceStarter(k))
+    }
+
+    c.Check(p.Diff(cp.Tables().Parameters()).IsEmpty(), Equals, true, Commentf(lang))
+  }
+}
+
+func (s *CompactSuite) TestTokenizer(c *C) {
+  str := "When Mr. Gregor Samsa woke up one morning from unsettling dreams, he found himself changed in his bed into a monstrous vermin. He was lying on his back as hard as armor plate. His many legs were waving helplessly before his eyes."
+
+  t := new(Tokenizer)
+  t.SetLanguage("english")
+  expected := t.SentencesFromText(str)
+
+  t.SetParameterSet(LoadLanguage("english").Compact())
+  c.Check(t.SentencesFromText(str), DeepEquals, expected)
+}
+
+// keys of every length that differ in only one byte, as types are hashed a
+// word at a time
+func (s *CompactSuite) TestKeyLengths(c *C) {
+  p := new(LanguageParameters)
+  var missing []string
+
+  for n := 1; n <= 24; n++ {
+    for i := 0; i < n; i++ {
+      b := []byte(strings.Repeat("a", n))
+      b[i] = 'b'
+      p.SaveAbbrevType(string(b))
+
+      b[i] = 'c'
+      missing = append(missing, string(b))
+    }
+  }
+
+  cp := p.Compact()
+
+  for k := range p.AbbrevTypes {
+    c.Check(cp.HasAbbrevType(k), Equals, true, Commentf("%q", k))
+  }
+
+  for _, k := range missing {
+    c.Check(cp.HasAbbrevType(k), Equals, false, Commentf("%q", k))
+  }
+}
+
+// The types are copied, as the words of a text share no memory with the
+// model, and comparing strings that share their bytes skips reading them
+func benchmarkLookups(b *testing.B, p ParameterSet, types []string) {
+  copied := make([]string, len(types))
+  for i, t := range types {
+    copied[i] = string([]byte(t))
+  }
+  types = copied
+  b.ResetTimer()
+  for i := 0; i < b.N; i++ {
+    t := types[i % len(types)]
+    p.GetOrthographicContext(t)
+    p.HasAbbrevType(t)
+  }
+}
+
+func BenchmarkLookupMap(b *testing.B) {
+  p := LoadLanguage("german")
+  benchmarkLookups(b, p, p.Tables().OrthoTypes)
+}
+
+func BenchmarkLookupCompact(b *testing.B) {
+  p := LoadLanguage("german")
+  benchmarkLookups(b, p.Compact(), p.Tables().OrthoTypes)
+}
+
+// reports the heap held by all bundled languages in each form
+func benchmarkHeap(b *testing.B, load func(lang string) ParameterSet) {
+  var before, after runtime.MemStats
+
+  for i := 0; i < b.N; i++ {
+    runtime.GC()
+    runtime.ReadMemStats(&before)
+
+    kept := make([]ParameterSet, 0, len(LANGUAGES))
+    for _, lang := range LANGUAGES {
+      kept = append(kept, load(lang))
+    }
+
+    runtime.GC()
+    runtime.ReadMemStats(&after)
+    b.ReportMetric(float64(after.HeapAlloc - before.HeapAlloc), "heap-bytes")
+    runtime.KeepAlive(kept)
+  }
+}
+
+func BenchmarkHeapMap(b *testing.B) {
+  benchmarkHeap(b, func(lang string) ParameterSet { return LoadLanguage(lang) })
+}
+
+func BenchmarkHeapCompact(b *testing.B) {
+  benchmarkHeap(b, func(lang string) ParameterSet { return LoadLanguage(lang).Compact() })
+}
+
+func benchmarkCollocations(b *testing.B, p ParameterSet, pairs [][2]string) {
+  copied := make([][2]string, len(pairs))
+  for i, pair := range pairs {
+    copied[i] = [2]string{string([]byte(pair[0])), string([]byte(pair[1]))}
+  }
+  pairs = copied
+  b.ReportAllocs()
+  b.ResetTimer()
+  for i := 0; i < b.N; i++ {
+    pair := pairs[i % len(pairs)]
+    p.HasCollocation(pair[0], pair[1])
+  }
+}
+
+func BenchmarkCollocationMap(b *testing.B) {
+  p := LoadLanguage("english")
+  benchmarkCollocations(b, p, p.Tables().Collocations)
+}
+
+func BenchmarkCollocationCompact(b *testing.B) {
+  p := LoadLanguage("english")
+  benchmarkCollocations(b, p.Compact(), p.Tables().Collocations)
+}
@@ -0,0 +1,113 @@
+package punkt
+
+import (
+  "context"
+  "crypto/sha256"
+  "encoding/hex"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "sync/atomic"
+  "time"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type FetchSuite struct{
+  server *httptest.Server
+  hits int32
+}
+
+var fetchSuite = Suite(&FetchSuite{})
+
+const fetchModel = `{"abbrev_types": ["dr"], "sentence_starters": [], "collocations": [], "ortho_context": {"dog": 32}}`
+
+func fetchChecksum(s string) string {
+  sum := sha256.Sum256([]byte(s))
+  return hex.EncodeToString(sum[:])
+}
+
+func (s *FetchSuite) SetUpTest(c *C) {
+  s.hits = 0
+  s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    atomic.AddInt32(&s.hits, 1)
+
+    switch r.URL.Path {
+    case "/english.json":
+      w.Write([]byte(fetchModel))
+    case "/huge.json":
+      w.Write([]byte(strings.Repeat(" ", 2048)))
+    case "/slow.json":
+      time.Sleep(200 * time.Millisecond)
+      w.Write([]byte(fetchModel))
+    default:
+      http.NotFound(w, r)
+    }
+  }))
+}
+
+func (s *FetchSuite) TearDownTest(c *C) {
+  s.server.Close()
+}
+
+func (s *FetchSuite) TestFetch(c *C) {
+  f := &ModelFetcher{}
+  p, err := f.FetchParameters(context.Background(), s.server.URL + "/english.json", fetchChecksum(fetchModel))
+
+  c.Assert(err, IsNil)
+  c.Check(p.HasAbbrevType("dr"), Equals, true)
+
+  c.Check(LoadParametersFromJSON(s.server.URL + "/english.json").HasAbbrevType("dr"), Equals, true)
+}
+
+func (s *FetchSuite) TestErrors(c *C) {
+  f := &ModelFetcher{MaxSize: 1024, Timeout: 50 * time.Millisecond}
+  ctx := context.Background()
+
+  _, err := f.Fetch(ctx, s.server.URL + "/missing.json", "")
+  c.Check(err, ErrorMatches, "punkt: fetching .*/missing.json: 404 Not Found")
+
+  _, err = f.Fetch(ctx, s.server.URL + "/huge.json", "")
+  c.Check(err, Equals, ErrModelTooLarge)
+
+  _, err = f.Fetch(ctx, s.server.URL + "/english.json", fetchChecksum("something else"))
+  c.Check(err, Equals, ErrChecksumMismatch)
+
+  for _, checksum := range []string{"abc", fetchChecksum(fetchModel) + "0", "../" + fetchChecksum(fetchModel)[3:], strings.Repeat("g", 64)} {
+    _, err = f.Fetch(ctx, s.server.URL + "/english.json", checksum)
+    c.Check(err, Equals, ErrBadChecksum, Commentf(checksum))
+  }
+
+  _, err = f.Fetch(ctx, s.server.URL + "/slow.json", "")
+  c.Check(err, ErrorMatches, ".*deadline exceeded.*")
+}
+
+func (s *FetchSuite) TestCache(c *C) {
+  dir := c.MkDir()
+  url := s.server.URL + "/english.json"
+  checksum := fetchChecksum(fetchModel)
+
+  f := &ModelFetcher{CacheDir: dir}
+  _, err := f.Fetch(context.Background(), url, checksum)
+  c.Assert(err, IsNil)
+  _, err = f.Fetch(context.Background(), url, checksum)
+  c.Assert(err, IsNil)
+  c.Check(atomic.LoadInt32(&s.hits), Equals, int32(1))
+
+  offline := &ModelFetcher{CacheDir: dir, Offline: true}
+  contents, err := offline.Fetch(context.Background(), url, checksum)
+  c.Assert(err, IsNil)
+  c.Check(string(contents), Equals, fetchModel)
+
+  _, err = offline.Fetch(context.Background(), s.server.URL + "/other.json", "")
+  c.Check(err, Equals, ErrNotCached)
+  c.Check(atomic.LoadInt32(&s.hits), Equals, int32(1))
+
+  // checked before the cache is read
+  _, err = offline.Fetch(context.Background(), url, "../" + checksum)
+  c.Check(err, Equals, ErrBadChecksum)
+
+  // upper case is fine
+  _, err = offline.Fetch(context.Background(), url, strings.ToUpper(checksum))
+  c.Check(err, IsNil)
+}
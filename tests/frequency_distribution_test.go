@@ -17,7 +17,7 @@ func (s *FrequencyDistributionSuite) SetUpSuite(c *C) {
 }
 
 func (s *FrequencyDistributionSuite) TestIncrementCountOnGivenSample(c *C) {
-  Fd := new(punkt.FrequencyDistribution)
+  Fd := new(punkt.FrequencyDistribution[string])
 
   for _, word := range s.Words {
     Fd.Inc(word)
@@ -30,7 +30,7 @@ func (s *FrequencyDistributionSuite) TestIncrementCountOnGivenSample(c *C) {
 }
 
 func (s *FrequencyDistributionSuite) TestIncrementByCountOnGivenSample(c *C) {
-  Fd := new(punkt.FrequencyDistribution)
+  Fd := new(punkt.FrequencyDistribution[string])
 
   for _, word := range s.Words {
     Fd.IncBy(word, 2)
@@ -43,7 +43,7 @@ func (s *FrequencyDistributionSuite) TestIncrementByCountOnGivenSample(c *C) {
 }
 
 func (s *FrequencyDistributionSuite) TestDirectCountAttribution(c *C) {
-  Fd := new(punkt.FrequencyDistribution)
+  Fd := new(punkt.FrequencyDistribution[string])
 
   Fd.Set("one", 10)
   Fd.Set("two", 20)
@@ -56,7 +56,7 @@ func (s *FrequencyDistributionSuite) TestDirectCountAttribution(c *C) {
 }
 
 func (s *FrequencyDistributionSuite) TestGetSampleFrequencies(c *C) {
-  Fd := new(punkt.FrequencyDistribution)
+  Fd := new(punkt.FrequencyDistribution[string])
 
   for _, word := range s.Words {
     Fd.Inc(word)
@@ -67,31 +67,66 @@ func (s *FrequencyDistributionSuite) TestGetSampleFrequencies(c *C) {
 }
 
 func (s *FrequencyDistributionSuite) TestSampleWithMaxOccurrences(c *C) {
-  Fd := new(punkt.FrequencyDistribution)
+  Fd := new(punkt.FrequencyDistribution[string])
 
   for _, word := range s.Words {
     Fd.Inc(word)
   }
 
-  c.Check(Fd.Max(), DeepEquals, punkt.SampleCount{"one",4})
+  c.Check(Fd.Max(), DeepEquals, punkt.SampleCount[string]{"one",4})
 }
 
 func (s *FrequencyDistributionSuite) TestOrderedKeyRetrieval(c *C) {
-  Fd := new(punkt.FrequencyDistribution)
+  Fd := new(punkt.FrequencyDistribution[string])
 
   for _, word := range s.Words {
     Fd.Inc(word)
   }
 
-  c.Check(Fd.OrderedSamples(), DeepEquals, []punkt.SampleCount{{"one",4},{"two",3},{"three",2}})
+  c.Check(Fd.OrderedSamples(), DeepEquals, []punkt.SampleCount[string]{{"one",4},{"two",3},{"three",2}})
 }
 
 // FIXME: Do we need delete?
 
 func (s *FrequencyDistributionSuite) TestEmptyDistribution(c *C) {
-  Fd := new(punkt.FrequencyDistribution)
+  Fd := new(punkt.FrequencyDistribution[string])
 
   c.Check(Fd.Get("a sample"), Equals, 0)
   c.Check(Fd.N, Equals, 0)
   c.Check(Fd.FrequencyOf("a sample"), Equals, float64(0))
 }
+
+func (s *FrequencyDistributionSuite) TestCollocationSamples(c *C) {
+  Fd := new(punkt.FrequencyDistribution[punkt.Collocation])
+
+  Fd.Inc(punkt.Collocation{"a|b", "c"})
+  Fd.Inc(punkt.Collocation{"a", "b|c"})
+  Fd.Inc(punkt.Collocation{"a", "b|c"})
+
+  c.Check(Fd.Get(punkt.Collocation{"a|b", "c"}), Equals, 1)
+  c.Check(Fd.Get(punkt.Collocation{"a", "b|c"}), Equals, 2)
+  c.Check(Fd.Max(), DeepEquals, punkt.SampleCount[punkt.Collocation]{punkt.Collocation{"a", "b|c"}, 2})
+}
+
+func (s *FrequencyDistributionSuite) TestTiesInSampleOrder(c *C) {
+  Fd := new(punkt.FrequencyDistribution[string])
+
+  for _, word := range []string{"pear", "fig", "apple", "fig", "kiwi"} {
+    Fd.Inc(word)
+  }
+
+  c.Check(Fd.OrderedSamples(), DeepEquals, []punkt.SampleCount[string]{{"fig",2},{"apple",1},{"kiwi",1},{"pear",1}})
+
+  Fd.Inc("pear")
+  c.Check(Fd.Max(), DeepEquals, punkt.SampleCount[string]{"fig",2})
+}
+
+func (s *FrequencyDistributionSuite) TestCollocationTiesInSampleOrder(c *C) {
+  Fd := new(punkt.FrequencyDistribution[punkt.Collocation])
+
+  Fd.Inc(punkt.Collocation{"b", "a"})
+  Fd.Inc(punkt.Collocation{"a", "c"})
+  Fd.Inc(punkt.Collocation{"a", "b"})
+
+  c.Check(Fd.OrderedSamples(), DeepEquals, []punkt.SampleCount[punkt.Collocation]{{punkt.Collocation{"a", "b"}, 1}, {punkt.Collocation{"a", "c"}, 1}, {punkt.Collocation{"b", "a"}, 1}})
+}
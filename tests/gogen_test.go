@@ -0,0 +1,56 @@
+package punkt
+
+import (
+  "go/parser"
+  "go/token"
+  "strings"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type GoGenSuite struct{
+  params *LanguageParameters
+}
+
+var goGenSuite = Suite(&GoGenSuite{})
+
+func (s *GoGenSuite) SetUpTest(c *C) {
+  s.params = new(LanguageParameters)
+  s.params.SaveAbbrevType("u.s")
+  s.params.SaveAbbrevType("dr")
+  s.params.SaveSentenceStarter("the")
+  s.params.SaveCollocation("jan", "15")
+  s.params.SetOrthographicContext("dog", ORTHO_MID_LC)
+  s.params.SetOrthographicContext("\"quoted\"", ORTHO_BEG_UC)
+  s.params.Metadata.Language = "en"
+}
+
+func (s *GoGenSuite) TestTables(c *C) {
+  t := s.params.Tables()
+
+  c.Check(t.AbbrevTypes, DeepEquals, []string{"dr", "u.s"})
+  c.Check(t.Collocations, DeepEquals, [][2]string{{"jan", "15"}})
+  c.Check(t.OrthoTypes, DeepEquals, []string{"\"quoted\"", "dog"})
+  c.Check(t.OrthoFlags, DeepEquals, []OrthoContext{ORTHO_BEG_UC, ORTHO_MID_LC})
+
+  p := t.Parameters()
+  c.Check(s.params.Diff(p).IsEmpty(), Equals, true)
+  c.Check(p.Metadata.Language, Equals, "en")
+}
+
+func (s *GoGenSuite) TestGenerateGoSource(c *C) {
+  src, err := GenerateGoSource(s.params, "english", "data/english.json")
+  c.Assert(err, IsNil)
+
+  f, err := parser.ParseFile(token.NewFileSet(), "model.go", src, 0)
+  c.Assert(err, IsNil)
+  c.Check(f.Name.Name, Equals, "english")
+
+  code := string(src)
+  c.Check(strings.HasPrefix(code, "// Code generated by punktgen from data/english.json; DO NOT EDIT."), Equals, true)
+  c.Check(strings.Contains(code, "\"u.s\",\n"), Equals, true)
+  c.Check(strings.Contains(code, "{\"jan\", \"15\"}"), Equals, true)
+  c.Check(strings.Contains(code, "\"\\\"quoted\\\"\",\n"), Equals, true)
+  c.Check(strings.Contains(code, "Language: \"en\""), Equals, true)
+  c.Check(strings.Contains(code, "func Parameters() *punkt.LanguageParameters"), Equals, true)
+}
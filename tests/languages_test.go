@@ -0,0 +1,75 @@
+package punkt
+
+import (
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type LanguagesSuite struct{}
+
+var languagesSuite = Suite(&LanguagesSuite{})
+
+func (s *LanguagesSuite) TestResolve(c *C) {
+  tags := map[string]string{
+    "english": "english",
+    "English": "english",
+    "en": "english",
+    "en-US": "english",
+    "en_GB": "english",
+    "pt-BR": "portuguese",
+    "de-CH": "german",
+    "deu": "german",
+    "nb": "norwegian",
+    "nn-NO": "norwegian",
+    "sl-Latn-SI": "slovene",
+  }
+
+  for tag, expected := range tags {
+    name, err := ResolveLanguage(tag)
+    c.Check(err, IsNil, Commentf(tag))
+    c.Check(name, Equals, expected, Commentf(tag))
+  }
+}
+
+func (s *LanguagesSuite) TestUnknown(c *C) {
+  _, err := ResolveLanguage("germn")
+  c.Assert(err, FitsTypeOf, &UnknownLanguageError{})
+  c.Check(err.(*UnknownLanguageError).Closest[0], Equals, "german")
+  c.Check(err, ErrorMatches, `punkt: unknown language "germn", closest supported languages are german, .*`)
+
+  _, err = ResolveLanguage("xx-YY")
+  c.Check(err, ErrorMatches, `punkt: unknown language "xx-YY".*`)
+
+  _, err = ResolveLanguage("")
+  c.Check(err, NotNil)
+
+  t := new(Tokenizer)
+  c.Check(t.SetLanguage("klingon"), FitsTypeOf, &UnknownLanguageError{})
+}
+
+func (s *LanguagesSuite) TestAliases(c *C) {
+  c.Assert(RegisterLanguageAlias("ca", "spanish"), IsNil)
+  c.Assert(RegisterLanguageAlias("pt-AO", "english"), IsNil)
+  defer RegisterLanguageAlias("ca", "")
+  defer RegisterLanguageAlias("pt-AO", "")
+
+  name, err := ResolveLanguage("ca-ES")
+  c.Check(err, IsNil)
+  c.Check(name, Equals, "spanish")
+
+  // more specific tags win over the default codes
+  name, _ = ResolveLanguage("pt-ao")
+  c.Check(name, Equals, "english")
+  name, _ = ResolveLanguage("pt-BR")
+  c.Check(name, Equals, "portuguese")
+
+  c.Check(RegisterLanguageAlias("ca", "catalan"), FitsTypeOf, &UnknownLanguageError{})
+  c.Check(RegisterLanguageAlias("", "english"), NotNil)
+}
+
+func (s *LanguagesSuite) TestSetLanguageTag(c *C) {
+  t := new(Tokenizer)
+  c.Assert(t.SetLanguage("de-CH"), IsNil)
+  c.Check(t.EnableAbbrevPack("legal"), IsNil)
+  c.Check(t.Parameters().HasAbbrevType("bgbl"), Equals, true)
+}
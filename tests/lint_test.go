@@ -0,0 +1,103 @@
+package punkt
+
+import (
+  "strings"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type LintSuite struct{}
+
+var lintSuite = Suite(&LintSuite{})
+
+const lintReference = `# word count
+the 1000
+de 900
+gol 500
+que 400
+ele 20
+zebra 1
+`
+
+func rules(report LintReport) (out []string) {
+  for _, f := range report {
+    out = append(out, f.Rule + " " + f.Field)
+  }
+
+  return
+}
+
+func (s *LintSuite) TestClean(c *C) {
+  p := new(LanguageParameters)
+  p.SaveAbbrevType("dr")
+  p.SaveAbbrevType("u.s")
+  p.SaveSentenceStarter("ele")
+  p.SaveCollocation("jan", "##number##")
+  p.SetOrthographicContext("ele", ORTHO_BEG_UC|ORTHO_MID_LC)
+
+  reference, err := LoadFrequencyList(strings.NewReader(lintReference))
+  c.Assert(err, IsNil)
+
+  report := p.Lint(LintOptions{Reference: reference})
+  c.Check(report, HasLen, 0)
+  c.Check(report.Max(), Equals, Severity(-1))
+}
+
+func (s *LintSuite) TestFindings(c *C) {
+  p := new(LanguageParameters)
+  p.SaveAbbrevType("gol")
+  p.SaveAbbrevType("etc.")
+  p.SaveAbbrevType("Mr")
+  p.SaveAbbrevType(". .")
+  p.SaveAbbrevType("")
+  p.SaveSentenceStarter("ele")
+  p.SaveSentenceStarter("zebra")
+  p.SaveCollocation("--", "...")
+  p.SaveCollocation("(", "a")
+  p.SetOrthographicContext("ele", ORTHO_MID_LC)
+  p.SetOrthographicContext("##number##", ORTHO_MID_UC)
+
+  reference, err := LoadFrequencyList(strings.NewReader(lintReference))
+  c.Assert(err, IsNil)
+
+  report := p.Lint(LintOptions{Reference: reference, CommonWordRank: 3, RareStarterRank: 4})
+  c.Check(rules(report), DeepEquals, []string{
+    `invalid abbrev_types[""]`,
+    `type_space abbrev_types[". ."]`,
+    `abbrev_period abbrev_types[". ."]`,
+    `type_case abbrev_types["Mr"]`,
+    `abbrev_period abbrev_types["etc."]`,
+    `common_word_abbrev abbrev_types["gol"]`,
+    `punctuation_collocation collocations["--|..."]`,
+    `ortho_no_case ortho_context["##number##"]`,
+    `starter_lower_case sentence_starters["ele"]`,
+    `rare_starter sentence_starters["ele"]`,
+    `rare_starter sentence_starters["zebra"]`,
+    `punctuation_collocation collocations["(|a"]`,
+  })
+
+  c.Check(report.Max(), Equals, SEVERITY_ERROR)
+  c.Check(report.AtLeast(SEVERITY_WARNING), HasLen, 11)
+  c.Check(report[5].Message, Equals, "abbreviation is the 3rd most common word in the reference")
+  c.Check(report[9].String(), Equals, `warning: sentence_starters["ele"]: sentence starter is only the 5th most common word in the reference (rare_starter)`)
+}
+
+func (s *LintSuite) TestFrequencyList(c *C) {
+  f, err := LoadFrequencyList(strings.NewReader("The 3\nthe 2\n\nof 1\n"))
+  c.Assert(err, IsNil)
+  c.Check(f.Get("the"), Equals, 5)
+  c.Check(f.N, Equals, 6)
+
+  _, err = LoadFrequencyList(strings.NewReader("the\n"))
+  c.Check(err, ErrorMatches, `punkt: frequency list line 1: expected a word and a count, got "the"`)
+
+  _, err = LoadFrequencyList(strings.NewReader("the x\n"))
+  c.Check(err, ErrorMatches, `punkt: frequency list line 1: bad count "x"`)
+}
+
+func (s *LintSuite) TestBundledModels(c *C) {
+  for _, lang := range LANGUAGES {
+    report := LoadLanguage(lang).Lint(LintOptions{})
+    c.Check(report.Max() < SEVERITY_ERROR, Equals, true, Commentf("%s:\n%s", lang, report.AtLeast(SEVERITY_ERROR)))
+  }
+}
@@ -0,0 +1,189 @@
+package punkt
+
+import (
+  "bytes"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "testing"
+  "time"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type MappedSuite struct {
+  dir string
+}
+
+var mappedSuite = Suite(&MappedSuite{})
+
+func (s *MappedSuite) SetUpTest(c *C) {
+  var err error
+  s.dir, err = ioutil.TempDir("", "punkt-mapped")
+  c.Assert(err, IsNil)
+}
+
+func (s *MappedSuite) TearDownTest(c *C) {
+  os.RemoveAll(s.dir)
+}
+
+func (s *MappedSuite) TestSameAnswers(c *C) {
+  for _, lang := range LANGUAGES {
+    p := LoadLanguage(lang)
+    path := filepath.Join(s.dir, lang + MAPPED_MODEL_EXT)
+    c.Assert(SaveParametersToMapped(p, path), IsNil)
+
+    m, err := OpenMappedParameters(path)
+    c.Assert(err, IsNil)
+
+    for k := range p.AbbrevTypes {
+      c.Assert(m.HasAbbrevType(k), Equals, true, Commentf("%s abbrev %q", lang, k))
+      c.Assert(m.HasAbbrevType(k + "x"), Equals, p.HasAbbrevType(k + "x"))
+    }
+
+    for k := range p.SentenceStarters {
+      c.Assert(m.HasSentenceStarter(k), Equals, true, Commentf("%s starter %q", lang, k))
+    }
+
+    for _, pair := range p.Tables().Collocations {
+      c.Assert(m.HasCollocation(pair[0], pair[1]), Equals, true, Commentf("%s collocation %q", lang, pair))
+      c.Assert(m.HasCollocation(pair[1], pair[0]), Equals, p.HasCollocation(pair[1], pair[0]))
+    }
+
+    for k, v := range p.OrthographicContext {
+      c.Assert(m.GetOrthographicContext(k), Equals, v, Commentf("%s ortho %q", lang, k))
+    }
+
+    c.Check(p.Diff(m.Tables().Parameters()).IsEmpty(), Equals, true, Commentf(lang))
+    c.Check(m.Close(), IsNil)
+  }
+}
+
+func (s *MappedSuite) TestStableBytes(c *C) {
+  p := LoadLanguage("english")
+  p.Metadata = ModelMetadata{Language: "en", CreatedAt: time.Date(2016, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+  var a, b bytes.Buffer
+  c.Assert(WriteMappedParameters(&a, p), IsNil)
+  c.Assert(WriteMappedParameters(&b, p.Copy()), IsNil)
+  c.Check(bytes.Equal(a.Bytes(), b.Bytes()), Equals, true)
+
+  m, err := NewMappedParameters(a.Bytes())
+  c.Assert(err, IsNil)
+  c.Check(m.Metadata, DeepEquals, p.Metadata)
+}
+
+func (s *MappedSuite) TestEmpty(c *C) {
+  var b bytes.Buffer
+  c.Assert(WriteMappedParameters(&b, new(LanguageParameters)), IsNil)
+
+  m, err := NewMappedParameters(b.Bytes())
+  c.Assert(err, IsNil)
+  c.Check(m.HasAbbrevType("dr"), Equals, false)
+  c.Check(m.HasAbbrevType(""), Equals, false)
+  c.Check(m.HasCollocation("a", "b"), Equals, false)
+  c.Check(m.Metadata.IsZero(), Equals, true)
+}
+
+// two types with the same FNV-1a hash can't be told apart by any seed
+func (s *MappedSuite) TestSameHash(c *C) {
+  p := new(LanguageParameters)
+  p.SaveAbbrevType("nlfadndekffbiohh")
+  p.SaveAbbrevType("pkoejpnkmapdgjgi")
+
+  var b bytes.Buffer
+  c.Check(WriteMappedParameters(&b, p), ErrorMatches, `punkt: types "nlfadndekffbiohh" and "pkoejpnkmapdgjgi" have the same hash`)
+
+  cp := p.Compact()
+  c.Check(cp.HasAbbrevType("nlfadndekffbiohh"), Equals, true)
+  c.Check(cp.HasAbbrevType("pkoejpnkmapdgjgi"), Equals, true)
+}
+
+func (s *MappedSuite) TestBadFiles(c *C) {
+  var b bytes.Buffer
+  c.Assert(WriteMappedParameters(&b, LoadLanguage("german")), IsNil)
+  good := b.Bytes()
+
+  _, err := NewMappedParameters([]byte(`{"abbrev_types": []}`))
+  c.Check(err, Equals, ErrBadMappedModel)
+
+  _, err = NewMappedParameters(good[:len(good)-1])
+  c.Check(err, Equals, ErrBadMappedModel)
+
+  newer := append([]byte(nil), good...)
+  newer[8] = 2
+  _, err = NewMappedParameters(newer)
+  c.Check(err, ErrorMatches, "punkt: memory mapped model has format version 2, expected 1")
+
+  // garbage in the tables gives wrong answers, not a crash
+  garbage := append([]byte(nil), good...)
+  for i := 32; i < len(garbage); i += 7 {
+    garbage[i] ^= 0xff
+  }
+  m, err := NewMappedParameters(garbage)
+  c.Assert(err, IsNil)
+  for k := range LoadLanguage("german").OrthographicContext {
+    m.GetOrthographicContext(k)
+    m.HasCollocation(k, k)
+  }
+  m.Tables()
+}
+
+func (s *MappedSuite) TestLoadAndTokenize(c *C) {
+  path := filepath.Join(s.dir, "english" + MAPPED_MODEL_EXT)
+  c.Assert(SaveParametersToMapped(LoadLanguage("english"), path), IsNil)
+
+  p, err := LoadParametersFromFile(path)
+  c.Assert(err, IsNil)
+  c.Check(p.Diff(LoadLanguage("english")).IsEmpty(), Equals, true)
+
+  m, err := OpenMappedParameters(path)
+  c.Assert(err, IsNil)
+  defer m.Close()
+
+  str := "When Mr. Gregor Samsa woke up one morning from unsettling dreams, he found himself changed in his bed into a monstrous vermin. He was lying on his back as hard as armor plate."
+  t := new(Tokenizer)
+  t.SetLanguage("english")
+  expected := t.SentencesFromText(str)
+
+  t.SetParameterSet(m)
+  c.Check(t.SentencesFromText(str), DeepEquals, expected)
+}
+
+func BenchmarkOpenJSON(b *testing.B) {
+  contents, _ := LoadLanguage("english").ToJSON()
+
+  b.ResetTimer()
+  for i := 0; i < b.N; i++ {
+    LoadParametersFromJSONString(contents)
+  }
+}
+
+func BenchmarkOpenMapped(b *testing.B) {
+  dir, _ := ioutil.TempDir("", "punkt-mapped")
+  defer os.RemoveAll(dir)
+  path := filepath.Join(dir, "english" + MAPPED_MODEL_EXT)
+  SaveParametersToMapped(LoadLanguage("english"), path)
+
+  b.ResetTimer()
+  for i := 0; i < b.N; i++ {
+    m, err := OpenMappedParameters(path)
+    if err != nil {
+      b.Fatal(err)
+    }
+    m.HasAbbrevType("dr")
+    m.Close()
+  }
+}
+
+func BenchmarkLookupMapped(b *testing.B) {
+  dir, _ := ioutil.TempDir("", "punkt-mapped")
+  defer os.RemoveAll(dir)
+  path := filepath.Join(dir, "german" + MAPPED_MODEL_EXT)
+  p := LoadLanguage("german")
+  SaveParametersToMapped(p, path)
+
+  m, _ := OpenMappedParameters(path)
+  defer m.Close()
+  benchmarkLookups(b, m, p.Tables().OrthoTypes)
+}
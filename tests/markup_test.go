@@ -0,0 +1,112 @@
+package punkt
+
+import (
+  "strings"
+
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type MarkupSuite struct{}
+
+var markupSuite = Suite(&MarkupSuite{})
+
+const markupHTML = `<!DOCTYPE html>
+<html>
+<head><title>Ignored</title><style>p { color: red }</style></head>
+<body>
+<nav><ul><li>Home<li>About</ul></nav>
+<script>if (a < b && c) { document.write("<p>Nope.</p>"); }</script>
+<h1>The club</h1>
+<p>Dr. Smith met the <b>gol</b>. team&nbsp;at noon.
+He was late.</p>
+<p>Then it rained<br>all day &amp; night.<p>Unclosed paragraph, and 1 < 2.
+<footer>Copyright</footer>
+</body>
+</html>`
+
+func (s *MarkupSuite) TestHTMLWords(c *C) {
+  words, err := SplitMarkupIntoMarkedWords(strings.NewReader(markupHTML), MarkupOptions{})
+
+  c.Assert(err, IsNil)
+  c.Check(words, DeepEquals, []string{
+    "The", "club", PARAGRAPH_BREAK,
+    "Dr.", "Smith", "met", "the", "gol.", "team", "at", "noon.", "He", "was", "late.", PARAGRAPH_BREAK,
+    "Then", "it", "rained", LINE_BREAK, "all", "day", "&", "night.", PARAGRAPH_BREAK,
+    "Unclosed", "paragraph", ",", "and", "1", "<", "2.",
+  })
+}
+
+// html is optional, so head may be at the top
+func (s *MarkupSuite) TestHTMLWithoutRoot(c *C) {
+  words, err := SplitMarkupIntoMarkedWords(strings.NewReader("<!DOCTYPE html><head><title>Nav</title></head><p>Text.</p>"), MarkupOptions{})
+
+  c.Assert(err, IsNil)
+  c.Check(words, DeepEquals, []string{"Text."})
+}
+
+const markupTEI = `<?xml version="1.0" encoding="UTF-8"?>
+<TEI xmlns="http://www.tei-c.org/ns/1.0">
+<teiHeader><fileDesc><titleStmt><title>A poem</title></titleStmt></fileDesc></teiHeader>
+<text><body>
+<div><head>Part one</head>
+<p>It was St. Agnes' Eve.<note>A feast day.</note> The owl was cold.</p>
+<lg><l>Bitter chill it was</l><l>The owl, for all his feathers</l></lg>
+<fw>Page 2</fw>
+</div>
+</body></text>
+</TEI>`
+
+func (s *MarkupSuite) TestTEIWords(c *C) {
+  words, err := SplitMarkupIntoMarkedWords(strings.NewReader(markupTEI), MarkupOptions{})
+
+  c.Assert(err, IsNil)
+  c.Check(words, DeepEquals, []string{
+    "Part", "one", PARAGRAPH_BREAK,
+    "It", "was", "St.", "Agnes", "'", "Eve.", "The", "owl", "was", "cold.", PARAGRAPH_BREAK,
+    "Bitter", "chill", "it", "was", LINE_BREAK, "The", "owl", ",", "for", "all", "his", "feathers",
+  })
+}
+
+func (s *MarkupSuite) TestOptions(c *C) {
+  words, err := SplitMarkupIntoMarkedWords(strings.NewReader("<doc><para>One.</para><skip>No.</skip><PARA>Two.</PARA><script/>Three.</doc>"), MarkupOptions{
+    BlockElements: []string{"para"},
+    SkipElements: []string{"skip"},
+  })
+
+  c.Assert(err, IsNil)
+  c.Check(words, DeepEquals, []string{"One.", PARAGRAPH_BREAK, "Two.", PARAGRAPH_BREAK, "Three."})
+}
+
+func (s *MarkupSuite) TestTrainWithMarkup(c *C) {
+  text := "The cat sat.\n\nApples are red\nFigs are not.\n\nThe end."
+  html := "<html><body><p>The cat sat.</p><p>Apples are red<br>Figs are not.</p><script>var x;</script><div>The end.</div></body></html>"
+
+  trainer := new(Trainer)
+  params, err := trainer.TrainWithMarkup(strings.NewReader(html), MarkupOptions{})
+
+  c.Assert(err, IsNil)
+  c.Check(params.OrthographicContext, DeepEquals, new(Trainer).TrainWithText(text).OrthographicContext)
+  c.Check(trainer.TypeFdist.N, Equals, 11)
+}
+
+func (s *MarkupSuite) TestOpenElementsAtEnd(c *C) {
+  for input, expected := range map[string][]string{
+    "<p>One.<p>Two.": {"One.", PARAGRAPH_BREAK, "Two."},
+    "<html><body><p>One.</p>": {"One."},
+    "Hello <b>there": {"Hello", "there"},
+    "<div><p>Cut off in the mid": {"Cut", "off", "in", "the", "mid"},
+  } {
+    words, err := SplitMarkupIntoMarkedWords(strings.NewReader(input), MarkupOptions{})
+
+    c.Check(err, IsNil, Commentf("%q", input))
+    c.Check(words, DeepEquals, expected, Commentf("%q", input))
+  }
+}
+
+func (s *MarkupSuite) TestTrainWithTruncatedMarkup(c *C) {
+  params, err := new(Trainer).TrainWithMarkup(strings.NewReader("<html><body><p>The cat sat.<p>Apples are red"), MarkupOptions{})
+
+  c.Assert(err, IsNil)
+  c.Check(params.GetOrthographicContext("apples"), Equals, ORTHO_BEG_UC)
+}
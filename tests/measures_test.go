@@ -0,0 +1,130 @@
+package punkt
+
+import (
+  "math"
+  "strings"
+
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type MeasuresSuite struct{}
+
+var measuresSuite = Suite(&MeasuresSuite{})
+
+func near(c *C, obtained, expected float64) {
+  c.Check(math.Abs(obtained - expected) < 1e-3, Equals, true, Commentf("obtained %v, expected %v", obtained, expected))
+}
+
+// G² computed from the full contingency table
+func gSquared(a, b, ab, n int) (g float64) {
+  observed := []float64{float64(ab), float64(a - ab), float64(b - ab), float64(n - a - b + ab)}
+  rows := []float64{float64(a), float64(a), float64(n - a), float64(n - a)}
+  cols := []float64{float64(b), float64(n - b), float64(b), float64(n - b)}
+
+  for i, o := range observed {
+    if o > 0 {
+      g += 2 * o * math.Log(o / (rows[i] * cols[i] / float64(n)))
+    }
+  }
+
+  return
+}
+
+func (s *MeasuresSuite) TestMeasures(c *C) {
+  // a and b are always seen together
+  near(c, ChiSquare(10, 10, 10, 100), 100)
+  near(c, PointwiseMutualInformation(10, 10, 10, 100), math.Log2(10))
+  near(c, TScore(10, 10, 10, 100), 9 / math.Sqrt(10))
+  near(c, FisherExact(3, 3, 3, 6), math.Log10(20))
+  near(c, FisherExact(10, 10, 10, 100), 13.2383)
+
+  // seen together less often than by chance
+  near(c, FisherExact(5, 5, 1, 20), -math.Log10(1 - 3003.0/15504))
+  c.Check(PointwiseMutualInformation(5, 5, 1, 20) < 0, Equals, true)
+  c.Check(math.IsInf(PointwiseMutualInformation(5, 5, 0, 20), -1), Equals, true)
+}
+
+func (s *MeasuresSuite) TestLogLikelihood(c *C) {
+  for _, t := range [][4]int{{10, 10, 10, 100}, {20, 30, 12, 500}, {100, 2000, 90, 100000}} {
+    near(c, ColLogLikelihood(t[0], t[1], t[2], t[3]), gSquared(t[0], t[1], t[2], t[3]))
+  }
+
+  near(c, ColLogLikelihood(20, 30, 10, 1000), 46.4966)
+}
+
+func (s *MeasuresSuite) TestFindAssociationMeasure(c *C) {
+  m, err := FindAssociationMeasure(MEASURE_CHI_SQUARE)
+  c.Assert(err, IsNil)
+  c.Check(m.Name(), Equals, "chi_square")
+  near(c, m.Score(10, 10, 10, 100), 100)
+
+  _, err = FindAssociationMeasure("dice")
+  c.Check(err, ErrorMatches, `punkt: unknown association measure "dice"`)
+
+  names := strings.Join(AssociationMeasures(), " ")
+  c.Check(names, Matches, "chi_square dunning fisher log_likelihood pmi t_score.*")
+}
+
+type constantMeasure float64
+
+func (m constantMeasure) Name() string {
+  return "test_constant"
+}
+
+func (m constantMeasure) Score(countA, countB, countAB, n int) float64 {
+  return float64(m)
+}
+
+func (s *MeasuresSuite) TestMeasurePerDecision(c *C) {
+  RegisterAssociationMeasure(constantMeasure(100))
+
+  settings := DefaultTrainerSettings()
+  settings.CollocationMeasure = "test_constant"
+  settings.CollocationCutoff = 50
+  settings.SentStarterMeasure = MEASURE_CHI_SQUARE
+
+  trainer := new(Trainer)
+  c.Assert(trainer.SetSettings(settings), IsNil)
+  p := trainer.TrainWithText(strings.Repeat(reportText, 5))
+
+  c.Check(p.Metadata.TrainerSettings.CollocationMeasure, Equals, "test_constant")
+  c.Check(p.Metadata.TrainerSettings.AbbrevMeasure, Equals, MEASURE_DUNNING)
+  c.Check(trainer.CollocationScore("dr", "smith"), Equals, float64(100))
+  c.Check(trainer.SentenceStarterScore("he"), Equals, ChiSquare(trainer.SentenceBreakCount, trainer.TypeFdist.Get("he"), trainer.SentenceStarterFdist.Get("he"), trainer.TypeFdist.N))
+
+  for _, cand := range trainer.Report().OfKind(CANDIDATE_COLLOCATION) {
+    c.Check(cand.Cutoff, Equals, float64(50))
+    c.Check(cand.Reason, Not(Equals), REASON_BELOW_CUTOFF)
+  }
+}
+
+func (s *MeasuresSuite) TestSetSettings(c *C) {
+  trainer := new(Trainer)
+  c.Check(trainer.Settings(), Equals, DefaultTrainerSettings())
+
+  settings := DefaultTrainerSettings()
+  settings.AbbrevMeasure = "dice"
+  c.Check(trainer.SetSettings(settings), ErrorMatches, `punkt: unknown association measure "dice"`)
+  c.Check(trainer.Settings(), Equals, DefaultTrainerSettings())
+
+  // empty names are the defaults
+  c.Assert(trainer.SetSettings(TrainerSettings{AbbrevCutoff: 0.5}), IsNil)
+  c.Check(trainer.Settings().AbbrevMeasure, Equals, MEASURE_DUNNING)
+  c.Check(trainer.Settings().CollocationMeasure, Equals, MEASURE_LOG_LIKELIHOOD)
+}
+
+func (s *MeasuresSuite) TestIgnoreAbbrevPenalty(c *C) {
+  settings := DefaultTrainerSettings()
+  settings.IgnoreAbbrevPenalty = true
+
+  trainer := new(Trainer)
+  c.Assert(trainer.SetSettings(settings), IsNil)
+  p := trainer.TrainWithText(strings.Repeat(reportText, 5))
+
+  // ignoring the penalty used to zero every score
+  c.Check(p.HasAbbrevType("dr"), Equals, true)
+
+  dr, _ := trainer.Report().Find(CANDIDATE_ABBREV_TYPE, "dr", "")
+  c.Check(dr.Score > 0, Equals, true)
+}
@@ -0,0 +1,92 @@
+package punkt
+
+import (
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "strings"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type OverlaySuite struct{
+  base *LanguageParameters
+}
+
+var overlaySuite = Suite(&OverlaySuite{})
+
+func (s *OverlaySuite) SetUpTest(c *C) {
+  s.base = new(LanguageParameters)
+  s.base.SaveAbbrevType("mr")
+  s.base.SaveAbbrevType("gol")
+  s.base.SetOrthographicContext("dog", ORTHO_MID_LC)
+}
+
+func (s *OverlaySuite) TestLookups(c *C) {
+  o := NewOverlay(s.base)
+  o.SaveAbbrevType("Approx.")
+  o.SaveNonAbbrevType("gol")
+  o.SaveCollocation("Jan", "15")
+  o.SaveSentenceStarter("However")
+
+  c.Check(o.HasAbbrevType("approx"), Equals, true)
+  c.Check(o.HasAbbrevType("mr"), Equals, true)
+  c.Check(o.HasAbbrevType("gol"), Equals, false)
+  c.Check(o.HasCollocation("jan", "15"), Equals, true)
+  c.Check(o.HasSentenceStarter("however"), Equals, true)
+  c.Check(o.GetOrthographicContext("dog"), Equals, ORTHO_MID_LC)
+
+  // the base is untouched
+  c.Check(s.base.HasAbbrevType("approx"), Equals, false)
+  c.Check(s.base.HasAbbrevType("gol"), Equals, true)
+
+  // overlays stack
+  o2 := NewOverlay(o)
+  o2.SaveAbbrevType("gol")
+  c.Check(o2.HasAbbrevType("gol"), Equals, true)
+  c.Check(o2.HasAbbrevType("approx"), Equals, true)
+}
+
+func (s *OverlaySuite) TestLoad(c *C) {
+  o := NewOverlay(s.base)
+  err := o.Load(strings.NewReader("# tenant list\nU.S.C.\ncir\n\n[non_abbrev_types]\ngol\n[collocations]\njan 15\n[sentence_starters]\nhowever\n"))
+
+  c.Assert(err, IsNil)
+  c.Check(o.HasAbbrevType("u.s.c"), Equals, true)
+  c.Check(o.HasAbbrevType("cir"), Equals, true)
+  c.Check(o.HasAbbrevType("gol"), Equals, false)
+  c.Check(o.HasCollocation("jan", "15"), Equals, true)
+  c.Check(o.HasSentenceStarter("however"), Equals, true)
+
+  err = o.Load(strings.NewReader("[collocations]\njan\n"))
+  c.Check(err, ErrorMatches, ".*line 2: collocation needs two types.*")
+
+  err = o.Load(strings.NewReader("[abbrevs]\nfoo\n"))
+  c.Check(err, ErrorMatches, ".*unknown section \"abbrevs\"")
+}
+
+func (s *OverlaySuite) TestLoadFromFile(c *C) {
+  dir := c.MkDir()
+  path := filepath.Join(dir, "tenant.txt")
+  c.Assert(ioutil.WriteFile(path, []byte("cf.\n"), 0644), IsNil)
+
+  o, err := LoadOverlayFromFile(s.base, path)
+  c.Assert(err, IsNil)
+  c.Check(o.HasAbbrevType("cf"), Equals, true)
+
+  _, err = LoadOverlayFromFile(s.base, filepath.Join(dir, "missing.txt"))
+  c.Check(os.IsNotExist(err), Equals, true)
+}
+
+func (s *OverlaySuite) TestTokenizerWithOverlay(c *C) {
+  str := "The dose was approx. two grams. It worked."
+
+  t := new(Tokenizer)
+  t.SetParameters(s.base)
+  c.Check(t.SentencesFromText(str), DeepEquals, []string{"The dose was approx.", "two grams.", "It worked."})
+
+  o := NewOverlay(s.base)
+  o.SaveAbbrevType("approx")
+  t.SetParameterSet(o)
+  c.Check(t.SentencesFromText(str), DeepEquals, []string{"The dose was approx. two grams.", "It worked."})
+}
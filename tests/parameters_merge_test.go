@@ -0,0 +1,96 @@
+package punkt
+
+import (
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type ParametersMergeSuite struct{
+  base *LanguageParameters
+  domain *LanguageParameters
+}
+
+var parametersMergeSuite = Suite(&ParametersMergeSuite{})
+
+func (s *ParametersMergeSuite) SetUpTest(c *C) {
+  s.base = new(LanguageParameters)
+  s.base.SaveAbbrevType("mr")
+  s.base.SaveAbbrevType("dr")
+  s.base.SaveSentenceStarter("the")
+  s.base.SaveCollocation("jan", "15")
+  s.base.SetOrthographicContext("dog", ORTHO_MID_LC)
+  s.base.SetOrthographicContext("cat", ORTHO_BEG_UC)
+
+  s.domain = new(LanguageParameters)
+  s.domain.SaveAbbrevType("dr")
+  s.domain.SaveAbbrevType("u.s.c")
+  s.domain.SaveSentenceStarter("however")
+  s.domain.SetOrthographicContext("dog", ORTHO_BEG_UC)
+}
+
+func (s *ParametersMergeSuite) TestUnion(c *C) {
+  p := s.base.Union(s.domain, ORTHO_MERGE_UNION)
+
+  c.Check(p.HasAbbrevType("mr"), Equals, true)
+  c.Check(p.HasAbbrevType("u.s.c"), Equals, true)
+  c.Check(p.HasSentenceStarter("however"), Equals, true)
+  c.Check(p.HasCollocation("jan", "15"), Equals, true)
+  c.Check(p.GetOrthographicContext("dog"), Equals, ORTHO_MID_LC | ORTHO_BEG_UC)
+
+  p = s.base.Union(s.domain, ORTHO_MERGE_RIGHT)
+  c.Check(p.GetOrthographicContext("dog"), Equals, ORTHO_BEG_UC)
+  c.Check(p.GetOrthographicContext("cat"), Equals, ORTHO_BEG_UC)
+
+  // inputs are left alone
+  c.Check(s.base.HasAbbrevType("u.s.c"), Equals, false)
+}
+
+func (s *ParametersMergeSuite) TestIntersect(c *C) {
+  p := s.base.Intersect(s.domain, ORTHO_MERGE_LEFT)
+
+  c.Check(len(p.AbbrevTypes), Equals, 1)
+  c.Check(p.HasAbbrevType("dr"), Equals, true)
+  c.Check(len(p.SentenceStarters), Equals, 0)
+  c.Check(p.GetOrthographicContext("dog"), Equals, ORTHO_MID_LC)
+  c.Check(p.GetOrthographicContext("cat"), Equals, OrthoContext(0))
+
+  p = s.base.Intersect(s.domain, ORTHO_MERGE_INTERSECT)
+  _, found := p.OrthographicContext["dog"]
+  c.Check(found, Equals, false)
+}
+
+func (s *ParametersMergeSuite) TestSubtract(c *C) {
+  s.domain.AddOrthographicContext("cat", ORTHO_BEG_UC)
+  p := s.base.Subtract(s.domain)
+
+  c.Check(p.HasAbbrevType("mr"), Equals, true)
+  c.Check(p.HasAbbrevType("dr"), Equals, false)
+  c.Check(p.HasSentenceStarter("the"), Equals, true)
+  c.Check(p.GetOrthographicContext("dog"), Equals, ORTHO_MID_LC)
+  _, found := p.OrthographicContext["cat"]
+  c.Check(found, Equals, false)
+}
+
+func (s *ParametersMergeSuite) TestDiff(c *C) {
+  d := s.base.Diff(s.domain)
+
+  c.Check(d.AbbrevTypesAdded, DeepEquals, []string{"u.s.c"})
+  c.Check(d.AbbrevTypesRemoved, DeepEquals, []string{"mr"})
+  c.Check(d.CollocationsRemoved, DeepEquals, []Collocation{{"jan", "15"}})
+  c.Check(d.SentenceStartersAdded, DeepEquals, []string{"however"})
+  c.Check(d.OrthoChanged, DeepEquals, []OrthoChange{
+    {Type: "cat", Old: ORTHO_BEG_UC, New: 0},
+    {Type: "dog", Old: ORTHO_MID_LC, New: ORTHO_BEG_UC},
+  })
+
+  c.Check(d.String(), Equals, `+ abbrev_types "u.s.c"
+- abbrev_types "mr"
+- collocations "jan|15"
++ sentence_starters "however"
+- sentence_starters "the"
+~ ortho_context "cat" BEG_UC -> 0
+~ ortho_context "dog" MID_LC -> BEG_UC
+`)
+
+  c.Check(s.base.Diff(s.base.Copy()).IsEmpty(), Equals, true)
+}
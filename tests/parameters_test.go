@@ -1,6 +1,7 @@
 package punkt
 
 import (
+  "time"
   . "github.com/harrisj/punkt"
   . "gopkg.in/check.v1"
 )
@@ -24,4 +25,79 @@ func (s *LanguageParametersSuite) TestOrthographicContext(c *C) {
 // func (s *LanguageParametersSuite) TestLoadFromJSON(c *C) {
 //   p := LoadParametersFromJSON("../data/english.json")
 //   c.Check(len(p.))
-// }
\ No newline at end of file
+// }
+
+func (s *LanguageParametersSuite) TestMetadataRoundTrip(c *C) {
+  p := new(LanguageParameters)
+  p.SaveAbbrevType("dr")
+  p.SaveSentenceStarter("the")
+  p.SaveCollocation("jan", "15")
+  p.SetOrthographicContext("dog", ORTHO_MID_LC)
+
+  settings := DefaultTrainerSettings()
+  p.Metadata = ModelMetadata{
+    Language: "en-US",
+    Corpus: "Wall Street Journal",
+    TokenCount: 469000,
+    TrainerSettings: &settings,
+    LibraryVersion: VERSION,
+    CreatedAt: time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC),
+  }
+
+  contents, err := p.ToJSON()
+  c.Assert(err, IsNil)
+
+  loaded := LoadParametersFromJSONString(contents)
+  c.Check(loaded.Metadata, DeepEquals, p.Metadata)
+  c.Check(loaded.Diff(p).IsEmpty(), Equals, true)
+
+  again, err := loaded.ToJSON()
+  c.Assert(err, IsNil)
+  c.Check(string(again), Equals, string(contents))
+}
+
+func (s *LanguageParametersSuite) TestNoMetadata(c *C) {
+  p := LoadParametersFromJSONString([]byte(`{"abbrev_types": ["dr"], "sentence_starters": [], "collocations": [], "ortho_context": {}}`))
+  c.Check(p.Metadata.IsZero(), Equals, true)
+
+  contents, err := p.ToJSON()
+  c.Assert(err, IsNil)
+  c.Check(string(contents), Equals, `{"sentence_starters":[],"abbrev_types":["dr"],"collocations":[],"ortho_context":{}}`)
+}
+
+func (s *LanguageParametersSuite) TestCollocationsWithPipes(c *C) {
+  p := new(LanguageParameters)
+  p.SaveCollocation("a|b", "c")
+  p.SaveCollocation("a", "b|c")
+  p.SaveCollocation(`x\`, "y")
+
+  c.Check(p.HasCollocation("a|b", "c"), Equals, true)
+  c.Check(p.HasCollocation("a", "b|c"), Equals, true)
+  c.Check(p.HasCollocation("a", "b"), Equals, false)
+
+  contents, err := p.ToJSON()
+  c.Assert(err, IsNil)
+  c.Check(string(contents), Equals, `{"sentence_starters":[],"abbrev_types":[],"collocations":["a|b\\|c","a\\|b|c","x\\\\|y"],"ortho_context":{}}`)
+
+  loaded, err := LoadParametersFromJSONStrict(contents)
+  c.Assert(err, IsNil)
+  c.Check(loaded.Diff(p).IsEmpty(), Equals, true)
+}
+
+func (s *LanguageParametersSuite) TestParseCollocation(c *C) {
+  colloc, err := ParseCollocation(`jan|15`)
+  c.Check(err, IsNil)
+  c.Check(colloc, Equals, Collocation{"jan", "15"})
+
+  colloc, err = ParseCollocation(`a\|b|c\\`)
+  c.Check(err, IsNil)
+  c.Check(colloc, Equals, Collocation{"a|b", `c\`})
+  c.Check(colloc.String(), Equals, `a\|b|c\\`)
+
+  _, err = ParseCollocation(`a|b|c`)
+  c.Check(err, ErrorMatches, `collocation "a\|b\|c" is not two types separated by \|`)
+
+  _, err = ParseCollocation(`a\b|c`)
+  c.Check(err, ErrorMatches, `collocation .* has a bad escape at byte 1`)
+}
+
@@ -0,0 +1,138 @@
+package punkt
+
+import (
+  "context"
+  "errors"
+  "strings"
+
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type ProgressSuite struct{}
+
+var progressSuite = Suite(&ProgressSuite{})
+
+func (s *ProgressSuite) TestPhases(c *C) {
+  var phases []TrainingPhase
+  var last TrainingProgress
+
+  trainer := new(Trainer)
+  p, err := trainer.TrainWithTextContext(context.Background(), strings.Repeat(reportText, 5), func(p TrainingProgress) {
+    if len(phases) == 0 || phases[len(phases)-1] != p.Phase {
+      phases = append(phases, p.Phase)
+    }
+    last = p
+  })
+
+  c.Assert(err, IsNil)
+  c.Check(phases, DeepEquals, []TrainingPhase{PHASE_COUNTING, PHASE_ABBREVIATIONS, PHASE_ORTHOGRAPHY, PHASE_CANDIDATES, PHASE_FINALIZE})
+  c.Check(last.Phase, Equals, PHASE_FINALIZE)
+  c.Check(last.Processed, Equals, last.Total)
+  c.Check(last.Types, Equals, len(trainer.TypeFdist.Counts))
+  c.Check(last.AbbrevTypes, Equals, len(p.AbbrevTypes))
+  c.Check(last.OrthoTypes, Equals, len(p.OrthographicContext))
+  c.Check(last.CollocationCandidates, Equals, len(trainer.CollocationFdist.Counts))
+}
+
+func (s *ProgressSuite) TestProgressInterval(c *C) {
+  var counted []int
+
+  _, err := new(Trainer).TrainWithTokenizedTextContext(context.Background(), strings.Fields(strings.Repeat("word ", 25000)), func(p TrainingProgress) {
+    if p.Phase == PHASE_COUNTING {
+      c.Check(p.Total, Equals, 25000)
+      counted = append(counted, p.Processed)
+    }
+  })
+
+  c.Assert(err, IsNil)
+  c.Check(counted, DeepEquals, []int{0, 10000, 20000, 25000})
+}
+
+func (s *ProgressSuite) TestCancel(c *C) {
+  ctx, cancel := context.WithCancel(context.Background())
+  defer cancel()
+
+  trainer := new(Trainer)
+  trainer.TrainWithText("The cat sat on the mat. The dog sat on the log.")
+  before := *trainer
+  beforeTypes := trainer.TypeFdist.Copy()
+
+  p, err := trainer.TrainWithTextContext(ctx, strings.Repeat(reportText, 5), func(p TrainingProgress) {
+    if p.Phase == PHASE_ABBREVIATIONS {
+      cancel()
+    }
+  })
+
+  c.Check(p, IsNil)
+  c.Check(errors.Is(err, context.Canceled), Equals, true)
+
+  var canceled *TrainingCanceledError
+  c.Assert(errors.As(err, &canceled), Equals, true)
+  c.Check(canceled.Phase, Equals, PHASE_ABBREVIATIONS)
+  c.Check(err, ErrorMatches, "punkt: training canceled in the abbreviations phase after [0-9]+ items: context canceled")
+
+  // nothing of the canceled run is left
+  c.Check(trainer.TypeFdist.N, Equals, before.TypeFdist.N)
+  c.Check(trainer.TypeFdist.Counts, DeepEquals, beforeTypes.Counts)
+  c.Check(trainer.PeriodTokensCount, Equals, before.PeriodTokensCount)
+  c.Check(trainer.SentenceBreakCount, Equals, before.SentenceBreakCount)
+  c.Check(trainer.Report(), DeepEquals, before.Report())
+}
+
+func (s *ProgressSuite) TestResumeAfterCancel(c *C) {
+  first := "The cat sat on the mat. The dog sat on the log."
+  second := strings.Repeat(reportText, 5)
+
+  expected := new(Trainer)
+  expected.TrainWithText(first)
+  want := expected.TrainWithText(second)
+
+  ctx, cancel := context.WithCancel(context.Background())
+  trainer := new(Trainer)
+  trainer.TrainWithText(first)
+  _, err := trainer.TrainWithTextContext(ctx, second, func(p TrainingProgress) {
+    if p.Phase == PHASE_FINALIZE {
+      cancel()
+    }
+  })
+  c.Assert(err, NotNil)
+
+  got, err := trainer.TrainWithTextContext(context.Background(), second, nil)
+  c.Assert(err, IsNil)
+  c.Check(got.AbbrevTypes, DeepEquals, want.AbbrevTypes)
+  c.Check(got.Collocations, DeepEquals, want.Collocations)
+  c.Check(got.SentenceStarters, DeepEquals, want.SentenceStarters)
+  c.Check(got.OrthographicContext, DeepEquals, want.OrthographicContext)
+  c.Check(trainer.TypeFdist.N, Equals, expected.TypeFdist.N)
+}
+
+func (s *ProgressSuite) TestAlreadyCanceled(c *C) {
+  ctx, cancel := context.WithCancel(context.Background())
+  cancel()
+
+  trainer := new(Trainer)
+  _, err := trainer.TrainWithTextContext(ctx, reportText, nil)
+
+  var canceled *TrainingCanceledError
+  c.Assert(errors.As(err, &canceled), Equals, true)
+  c.Check(canceled.Phase, Equals, PHASE_COUNTING)
+  c.Check(trainer.TypeFdist.N, Equals, 0)
+
+  _, err = trainer.TrainWithSentencesContext(ctx, strings.Split(goldText, "\n"), nil)
+  c.Check(errors.Is(err, context.Canceled), Equals, true)
+  c.Check(trainer.SentenceBreakCount, Equals, 0)
+}
+
+func (s *ProgressSuite) TestSupervisedProgress(c *C) {
+  var total int
+
+  _, err := new(Trainer).TrainWithSentencesContext(context.Background(), []string{"One.", "Two.", "Three."}, func(p TrainingProgress) {
+    if p.Phase == PHASE_COUNTING {
+      total = p.Total
+    }
+  })
+
+  c.Assert(err, IsNil)
+  c.Check(total, Equals, 3)
+}
@@ -0,0 +1,101 @@
+package punkt
+
+import (
+  "strings"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type PruneSuite struct{}
+
+var pruneSuite = Suite(&PruneSuite{})
+
+const pruneSample = "When Mr. Gregor Samsa woke up one morning from unsettling dreams, he found himself changed in his bed into a monstrous vermin. He was lying on his back as hard as armor plate. The U.S. economy grew 3.5 pct. in the third quarter of 1987, according to Dr. Smith of the Commerce Dept. in Washington. J. Smith said no."
+
+func (s *PruneSuite) TestRemoveIneffective(c *C) {
+  p := LoadLanguage("english")
+  pruned, report, err := Prune(p, PruneOptions{RemoveIneffective: true, Sample: pruneSample})
+
+  c.Assert(err, IsNil)
+  c.Check(report.OrthoRemoved > 0, Equals, true)
+  c.Check(len(pruned.OrthographicContext), Equals, len(p.OrthographicContext) - report.OrthoRemoved)
+  c.Check(report.BytesSaved() > 0, Equals, true)
+  c.Check(len(pruned.AbbrevTypes), Equals, len(p.AbbrevTypes))
+
+  // the second pass makes the same decisions for every removed type
+  for k := range p.OrthographicContext {
+    if _, kept := pruned.OrthographicContext[k]; kept {
+      continue
+    }
+
+    for _, word := range []string{k, strings.ToUpper(k[:1]) + k[1:]} {
+      for _, prev := range []string{"5", "J.", "etc.", "..."} {
+        before := AnnotateTokens(p, []*Token{MakeToken(prev), MakeToken(word)})
+        after := AnnotateTokens(pruned, []*Token{MakeToken(prev), MakeToken(word)})
+        c.Assert(after[0].Flags, Equals, before[0].Flags, Commentf("%s %s", prev, word))
+      }
+    }
+  }
+}
+
+func (s *PruneSuite) TestMinOrthoCount(c *C) {
+  p := new(LanguageParameters)
+  p.SetOrthographicContext("cat", ORTHO_MID_LC)
+  p.SetOrthographicContext("zebras", ORTHO_MID_LC)
+  p.SetOrthographicContext("dr", ORTHO_MID_LC)
+
+  counts := new(FrequencyDistribution[string])
+  for _, w := range []string{"cat", "cat", "zebras", "dr."} {
+    counts.Inc(w)
+  }
+
+  pruned, report, err := Prune(p, PruneOptions{TypeCounts: counts, MinOrthoCount: 2})
+  c.Assert(err, IsNil)
+
+  c.Check(pruned.GetOrthographicContext("cat"), Equals, ORTHO_MID_LC)
+  c.Check(pruned.GetOrthographicContext("zebras"), Equals, OrthoContext(0))
+  c.Check(pruned.GetOrthographicContext("dr"), Equals, OrthoContext(0))
+  c.Check(report.OrthoRemoved, Equals, 2)
+  c.Check(p.GetOrthographicContext("zebras"), Equals, ORTHO_MID_LC)
+}
+
+func (s *PruneSuite) TestScores(c *C) {
+  // statistics of a corpus where "the" starts 30 of 40 sentences
+  trainer := new(Trainer)
+  for i := 0; i < 1000; i++ {
+    trainer.TypeFdist.Inc("cat")
+  }
+  for i := 0; i < 60; i++ {
+    trainer.TypeFdist.Inc("the")
+  }
+  for i := 0; i < 30; i++ {
+    trainer.SentenceStarterFdist.Inc("the")
+  }
+  trainer.SentenceBreakCount = 40
+
+  score := trainer.SentenceStarterScore("the")
+  c.Assert(score > 0, Equals, true)
+  c.Check(trainer.SentenceStarterScore("zebras"), Equals, 0.0)
+  c.Check(trainer.CollocationScore("zebras", "ran"), Equals, 0.0)
+
+  p := new(LanguageParameters)
+  p.SaveSentenceStarter("the")
+  p.SaveSentenceStarter("zebras")
+  p.SaveCollocation("zebras", "ran")
+
+  pruned, report, err := Prune(p, PruneOptions{Trainer: trainer, MinStarterScore: score, MinCollocationScore: 1})
+  c.Assert(err, IsNil)
+  c.Check(pruned.HasSentenceStarter("the"), Equals, true)
+  c.Check(pruned.HasSentenceStarter("zebras"), Equals, false)
+  c.Check(pruned.Collocations, HasLen, 0)
+  c.Check(report.SentenceStartersRemoved, Equals, 1)
+  c.Check(report.CollocationsRemoved, Equals, 1)
+}
+
+func (s *PruneSuite) TestMissingStatistics(c *C) {
+  _, _, err := Prune(new(LanguageParameters), PruneOptions{MinOrthoCount: 2})
+  c.Check(err, ErrorMatches, ".*needs TypeCounts or a Trainer")
+
+  _, _, err = Prune(new(LanguageParameters), PruneOptions{MinStarterScore: 2})
+  c.Check(err, ErrorMatches, ".*needs the Trainer")
+}
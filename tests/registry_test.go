@@ -0,0 +1,178 @@
+package punkt
+
+import (
+  "context"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "sync"
+  "time"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type RegistrySuite struct {
+  dir string
+  path string
+  mtime time.Time
+}
+
+var registrySuite = Suite(&RegistrySuite{})
+
+func (s *RegistrySuite) SetUpTest(c *C) {
+  var err error
+  s.dir, err = ioutil.TempDir("", "punkt-registry")
+  c.Assert(err, IsNil)
+  s.path = filepath.Join(s.dir, "model.json")
+  s.mtime = time.Now().Add(-time.Hour)
+}
+
+func (s *RegistrySuite) TearDownTest(c *C) {
+  os.RemoveAll(s.dir)
+}
+
+// every write gets a later mtime, since file systems may not see the change
+// otherwise. The model is replaced with a rename, so Watch never sees a half
+// written file.
+func (s *RegistrySuite) write(c *C, contents string) {
+  tmp := s.path + ".tmp"
+  c.Assert(ioutil.WriteFile(tmp, []byte(contents), 0644), IsNil)
+  s.mtime = s.mtime.Add(time.Second)
+  c.Assert(os.Chtimes(tmp, s.mtime, s.mtime), IsNil)
+  c.Assert(os.Rename(tmp, s.path), IsNil)
+}
+
+func (s *RegistrySuite) TestReload(c *C) {
+  s.write(c, `{"abbrev_types": ["dr"]}`)
+
+  r := NewRegistry()
+  c.Assert(r.Load("en", s.path), IsNil)
+
+  first, found := r.Get("en")
+  c.Assert(found, Equals, true)
+  c.Check(first.Parameters.HasAbbrevType("dr"), Equals, true)
+  c.Check(first.Version, HasLen, 64)
+  c.Check(first.LoadedAt.IsZero(), Equals, false)
+
+  swapped, err := r.Reload("en")
+  c.Check(swapped, Equals, false)
+  c.Check(err, IsNil)
+
+  // touching the file is not a new version
+  s.write(c, `{"abbrev_types": ["dr"]}`)
+  swapped, err = r.Reload("en")
+  c.Check(swapped, Equals, false)
+  c.Check(err, IsNil)
+
+  s.write(c, `{"abbrev_types": ["dr", "mr"]}`)
+  swapped, err = r.Reload("en")
+  c.Check(swapped, Equals, true)
+  c.Check(err, IsNil)
+
+  second, _ := r.Get("en")
+  c.Check(second.Version, Not(Equals), first.Version)
+  c.Check(second.Parameters.HasAbbrevType("mr"), Equals, true)
+  c.Check(first.Parameters.HasAbbrevType("mr"), Equals, false)
+  c.Check(r.Parameters("en"), Equals, second.Parameters)
+}
+
+func (s *RegistrySuite) TestKeepsOldVersion(c *C) {
+  s.write(c, `{"abbrev_types": ["dr"]}`)
+
+  r := NewRegistry()
+  c.Assert(r.Load("en", s.path), IsNil)
+  old, _ := r.Get("en")
+
+  s.write(c, `{"abbrev_typos": ["dr"]}`)
+  swapped, err := r.Reload("en")
+  c.Check(swapped, Equals, false)
+  c.Check(err, ErrorMatches, `(?s).*model.json: punkt: 1 problems in model:.*did you mean "abbrev_types".*`)
+
+  current, _ := r.Get("en")
+  c.Check(current, Equals, old)
+
+  status, _ := r.Status("en")
+  c.Check(status.LastError, Equals, err)
+  c.Check(status.Current, Equals, old)
+
+  // reported once until the file changes again
+  _, err = r.Reload("en")
+  c.Check(err, IsNil)
+
+  s.write(c, `{"abbrev_types": ["dr", "mr"]}`)
+  swapped, err = r.Reload("en")
+  c.Check(swapped, Equals, true)
+  c.Check(err, IsNil)
+
+  status, _ = r.Status("en")
+  c.Check(status.LastError, IsNil)
+}
+
+func (s *RegistrySuite) TestCheck(c *C) {
+  s.write(c, `{"abbrev_types": ["dr"]}`)
+
+  r := NewRegistry()
+  r.Check = func(name string, p *LanguageParameters) error {
+    if report := p.Lint(LintOptions{}).AtLeast(SEVERITY_WARNING); len(report) > 0 {
+      return fmt.Errorf("%s", report)
+    }
+
+    return nil
+  }
+  c.Assert(r.Load("en", s.path), IsNil)
+
+  s.write(c, `{"abbrev_types": ["dr", "Mr"]}`)
+  _, err := r.Reload("en")
+  c.Check(err, ErrorMatches, `(?s).*model.json: .*type has upper case letters.*`)
+  c.Check(r.Parameters("en").HasAbbrevType("Mr"), Equals, false)
+
+  c.Check(r.Load("de", filepath.Join(s.dir, "missing.json")), NotNil)
+  _, found := r.Get("de")
+  c.Check(found, Equals, false)
+  c.Check(r.Names(), DeepEquals, []string{"en"})
+}
+
+func (s *RegistrySuite) TestWatch(c *C) {
+  s.write(c, `{"abbrev_types": ["dr"]}`)
+
+  r := NewRegistry()
+  c.Assert(r.Load("en", s.path), IsNil)
+
+  var mu sync.Mutex
+  var failures []string
+  r.OnError = func(name string, err error) {
+    mu.Lock()
+    failures = append(failures, name)
+    mu.Unlock()
+  }
+
+  ctx, cancel := context.WithCancel(context.Background())
+  done := make(chan bool)
+  go func() {
+    r.Watch(ctx, time.Millisecond)
+    done <- true
+  }()
+
+  old := r.Parameters("en")
+  s.write(c, `{"abbrev_types": ["dr", "mr"]}`)
+
+  deadline := time.Now().Add(5 * time.Second)
+  for !r.Parameters("en").HasAbbrevType("mr") && time.Now().Before(deadline) {
+    time.Sleep(time.Millisecond)
+  }
+  c.Check(r.Parameters("en").HasAbbrevType("mr"), Equals, true)
+
+  t := new(Tokenizer)
+  t.SetParameters(old)
+  c.Check(t.SentencesFromText("Ask Mr. Smith now. He knows."), HasLen, 3)
+  t.SetParameters(r.Parameters("en"))
+  c.Check(t.SentencesFromText("Ask Mr. Smith now. He knows."), HasLen, 2)
+
+  cancel()
+  <-done
+
+  mu.Lock()
+  c.Check(failures, HasLen, 0)
+  mu.Unlock()
+}
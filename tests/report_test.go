@@ -0,0 +1,133 @@
+package punkt
+
+import (
+  "bytes"
+  "encoding/csv"
+  "encoding/json"
+  "math"
+  "strings"
+
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type ReportSuite struct{}
+
+var reportSuite = Suite(&ReportSuite{})
+
+const reportText = "Dr. Smith met the gol. team. He saw Mr. Jones at the club. The gol. was late. Gol is a word. "
+
+func trainedReport() (*LanguageParameters, TrainingReport) {
+  trainer := new(Trainer)
+  p := trainer.TrainWithText(strings.Repeat(reportText, 5))
+  return p, trainer.Report()
+}
+
+func (s *ReportSuite) TestAbbrevCandidates(c *C) {
+  p, report := trainedReport()
+
+  c.Check(report.TokenCount, Equals, 105)
+  c.Check(p.HasAbbrevType("dr"), Equals, true)
+  c.Check(p.HasAbbrevType("gol"), Equals, false)
+
+  dr, found := report.Find(CANDIDATE_ABBREV_TYPE, "dr", "")
+  c.Assert(found, Equals, true)
+  c.Check(dr.Accepted, Equals, true)
+  c.Check(dr.Reason, Equals, REASON_SCORE)
+  c.Check(dr.Count, Equals, 5)
+  c.Check(dr.Cutoff, Equals, ABBREV_CUTOFF)
+  c.Check(dr.Score >= ABBREV_CUTOFF, Equals, true)
+
+  // gol is also seen without a period, which is penalized
+  gol, found := report.Find(CANDIDATE_ABBREV_TYPE, "gol", "")
+  c.Assert(found, Equals, true)
+  c.Check(gol.Accepted, Equals, false)
+  c.Check(gol.Reason, Equals, REASON_BELOW_CUTOFF)
+  c.Check(gol.Count, Equals, 10)
+  c.Check(gol.TypeCount, Equals, 15)
+  c.Check(gol.Margin() < 0, Equals, true)
+}
+
+func (s *ReportSuite) TestDecisionsMatchParameters(c *C) {
+  p, report := trainedReport()
+
+  for _, cand := range report.Candidates {
+    switch cand.Kind {
+    case CANDIDATE_ABBREV_TYPE:
+      c.Check(p.HasAbbrevType(cand.Type), Equals, cand.Accepted, Commentf("%s", cand.Type))
+    case CANDIDATE_SENTENCE_STARTER:
+      c.Check(p.HasSentenceStarter(cand.Type), Equals, cand.Accepted, Commentf("%s", cand.Type))
+    case CANDIDATE_COLLOCATION:
+      c.Check(p.HasCollocation(cand.Type, cand.Type2), Equals, cand.Accepted, Commentf("%s %s", cand.Type, cand.Type2))
+    }
+  }
+
+  c.Check(len(report.OfKind(CANDIDATE_COLLOCATION)) > 0, Equals, true)
+}
+
+func (s *ReportSuite) TestSort(c *C) {
+  report := TrainingReport{Candidates: []TrainingCandidate{
+    {Kind: CANDIDATE_COLLOCATION, Type: "a", Type2: "b", Count: 1, Score: 9, Cutoff: 7.88},
+    {Kind: CANDIDATE_ABBREV_TYPE, Type: "z", Count: 3, Score: 0.05, Cutoff: 0.3},
+    {Kind: CANDIDATE_ABBREV_TYPE, Type: "y", Count: 7, Score: 0.5, Cutoff: 0.3},
+    {Kind: CANDIDATE_ABBREV_TYPE, Type: "x", Count: 3, Score: 2, Cutoff: 0.3},
+  }}
+
+  types := func() (out []string) {
+    for _, cand := range report.Candidates {
+      out = append(out, cand.Type)
+    }
+    return
+  }
+
+  c.Check(report.Sort("kind"), IsNil)
+  c.Check(types(), DeepEquals, []string{"x", "y", "z", "a"})
+
+  c.Check(report.Sort("count"), IsNil)
+  c.Check(types(), DeepEquals, []string{"y", "x", "z", "a"})
+
+  c.Check(report.Sort("score"), IsNil)
+  c.Check(types(), DeepEquals, []string{"x", "y", "z", "a"})
+
+  c.Check(report.Sort("margin"), IsNil)
+  c.Check(types(), DeepEquals, []string{"y", "z", "x", "a"})
+
+  c.Check(report.Sort("length"), ErrorMatches, `punkt: can't sort a training report by "length"`)
+}
+
+func (s *ReportSuite) TestWriteCSV(c *C) {
+  report := TrainingReport{Candidates: []TrainingCandidate{
+    {CANDIDATE_COLLOCATION, "jan", "15", 4, 6, 9, 12.5, COLLOCATION_CUTOFF, true, REASON_SCORE},
+  }}
+
+  var b bytes.Buffer
+  c.Assert(report.WriteCSV(&b), IsNil)
+
+  rows, err := csv.NewReader(&b).ReadAll()
+  c.Assert(err, IsNil)
+  c.Check(rows, DeepEquals, [][]string{
+    {"kind", "type", "type2", "count", "type_count", "type2_count", "score", "cutoff", "accepted", "reason"},
+    {"collocation", "jan", "15", "4", "6", "9", "12.5", "7.88", "true", "score"},
+  })
+}
+
+func (s *ReportSuite) TestWriteJSON(c *C) {
+  _, report := trainedReport()
+
+  var b bytes.Buffer
+  c.Assert(report.WriteJSON(&b), IsNil)
+
+  var read TrainingReport
+  c.Assert(json.Unmarshal(b.Bytes(), &read), IsNil)
+  c.Check(read, DeepEquals, report)
+}
+
+func (s *ReportSuite) TestJSONScoreNotFinite(c *C) {
+  b, err := json.Marshal(TrainingCandidate{Kind: CANDIDATE_SENTENCE_STARTER, Type: "the", Score: math.Inf(-1)})
+  c.Assert(err, IsNil)
+  c.Check(string(b), Equals, `{"kind":"sentence_starter","type":"the","count":0,"type_count":0,"score":null,"cutoff":0,"accepted":false,"reason":""}`)
+
+  var read TrainingCandidate
+  c.Assert(json.Unmarshal(b, &read), IsNil)
+  c.Check(math.IsNaN(read.Score), Equals, true)
+}
@@ -0,0 +1,122 @@
+package punkt
+
+import (
+  "strings"
+
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type SeedsSuite struct{}
+
+var seedsSuite = Suite(&SeedsSuite{})
+
+func (s *SeedsSuite) TestForcedAbbrevType(c *C) {
+  trainer := new(Trainer)
+  trainer.Seeds = NewOverlay(nil)
+  trainer.Seeds.SaveAbbrevType("gol.")
+  trainer.Seeds.SaveAbbrevType("cf")
+
+  p := trainer.TrainWithText(strings.Repeat(reportText, 5))
+  c.Check(p.HasAbbrevType("gol"), Equals, true)
+  c.Check(p.HasAbbrevType("cf"), Equals, true)
+  c.Check(p.HasAbbrevType("dr"), Equals, true)
+
+  report := trainer.Report()
+
+  // the score is still reported, but the seed decides
+  gol, _ := report.Find(CANDIDATE_ABBREV_TYPE, "gol", "")
+  c.Check(gol.Accepted, Equals, true)
+  c.Check(gol.Reason, Equals, REASON_SEED)
+  c.Check(gol.Seeded(), Equals, true)
+  c.Check(gol.Score < ABBREV_CUTOFF, Equals, true)
+
+  // never seen in the text
+  cf, found := report.Find(CANDIDATE_ABBREV_TYPE, "cf", "")
+  c.Assert(found, Equals, true)
+  c.Check(cf.Accepted, Equals, true)
+  c.Check(cf.Count, Equals, 0)
+  c.Check(cf.Reason, Equals, REASON_SEED)
+
+  dr, _ := report.Find(CANDIDATE_ABBREV_TYPE, "dr", "")
+  c.Check(dr.Seeded(), Equals, false)
+  c.Check(dr.Reason, Equals, REASON_SCORE)
+}
+
+func (s *SeedsSuite) TestForbiddenAbbrevType(c *C) {
+  trainer := new(Trainer)
+  trainer.Seeds = NewOverlay(nil)
+  trainer.Seeds.SaveNonAbbrevType("Dr")
+
+  p := trainer.TrainWithText(strings.Repeat(reportText, 5))
+  c.Check(p.HasAbbrevType("dr"), Equals, false)
+  c.Check(p.HasAbbrevType("mr"), Equals, true)
+
+  dr, found := trainer.Report().Find(CANDIDATE_ABBREV_TYPE, "dr", "")
+  c.Assert(found, Equals, true)
+  c.Check(dr.Accepted, Equals, false)
+  c.Check(dr.Reason, Equals, REASON_FORBIDDEN)
+  c.Check(dr.Score >= ABBREV_CUTOFF, Equals, true)
+}
+
+func (s *SeedsSuite) TestStatisticsRespectSeeds(c *C) {
+  text := strings.Repeat("We met Gol. Smith today. ", 10)
+
+  plain := new(Trainer)
+  plain.Seeds = NewOverlay(nil)
+  plain.Seeds.SaveNonAbbrevType("gol")
+  plain.TrainWithText(text)
+
+  seeded := new(Trainer)
+  seeded.Seeds = NewOverlay(nil)
+  seeded.Seeds.SaveAbbrevType("gol")
+  seeded.TrainWithText(text)
+
+  // a forced abbreviation is no sentence break, so what follows it is no
+  // sentence starter
+  c.Check(plain.SentenceBreakCount, Equals, 20)
+  c.Check(seeded.SentenceBreakCount, Equals, 10)
+  c.Check(plain.SentenceStarterFdist.Get("smith"), Equals, 10)
+  c.Check(seeded.SentenceStarterFdist.Get("smith"), Equals, 0)
+}
+
+func (s *SeedsSuite) TestStartersAndCollocations(c *C) {
+  trainer := new(Trainer)
+  trainer.Seeds = NewOverlay(nil)
+  trainer.Seeds.SaveSentenceStarter("However")
+  trainer.Seeds.SaveCollocation("jan", "##number##")
+
+  p := trainer.TrainWithText(strings.Repeat(reportText, 5))
+  c.Check(p.HasSentenceStarter("however"), Equals, true)
+  c.Check(p.HasCollocation("jan", "##number##"), Equals, true)
+
+  report := trainer.Report()
+
+  however, found := report.Find(CANDIDATE_SENTENCE_STARTER, "however", "")
+  c.Assert(found, Equals, true)
+  c.Check(however.Reason, Equals, REASON_SEED)
+  c.Check(however.Count, Equals, 0)
+
+  jan, found := report.Find(CANDIDATE_COLLOCATION, "jan", "##number##")
+  c.Assert(found, Equals, true)
+  c.Check(jan.Reason, Equals, REASON_SEED)
+
+  for _, cand := range report.Candidates {
+    if cand.Kind == CANDIDATE_COLLOCATION && cand.Type != "jan" {
+      c.Check(cand.Seeded(), Equals, false)
+    }
+  }
+}
+
+func (s *SeedsSuite) TestSupervisedSeeds(c *C) {
+  trainer := new(Trainer)
+  trainer.Seeds = NewOverlay(nil)
+  c.Assert(trainer.Seeds.Load(strings.NewReader("etc\n[non_abbrev_types]\njan\n")), IsNil)
+
+  p := trainer.TrainWithSegmentedText(strings.Repeat(goldText, 2))
+  c.Check(p.AbbrevTypes, DeepEquals, map[string]bool{"dr": true, "mr": true, "etc": true})
+
+  jan, _ := trainer.Report().Find(CANDIDATE_ABBREV_TYPE, "jan", "")
+  c.Check(jan.Reason, Equals, REASON_FORBIDDEN)
+  c.Check(jan.Count, Equals, 2)
+}
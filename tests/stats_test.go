@@ -0,0 +1,80 @@
+package punkt
+
+import (
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type StatsSuite struct{}
+
+var statsSuite = Suite(&StatsSuite{})
+
+func (s *StatsSuite) TestStats(c *C) {
+  p := new(LanguageParameters)
+  p.SaveAbbrevType("dr")
+  p.SaveAbbrevType("mr")
+  p.SaveAbbrevType("u.s")
+  p.SaveAbbrevType("bzgl")
+  p.SaveCollocation("jan", "15")
+  p.SaveSentenceStarter("the")
+  p.SetOrthographicContext("dog", ORTHO_MID_LC)
+  p.SetOrthographicContext("cat", ORTHO_MID_LC)
+  p.SetOrthographicContext("the", ORTHO_BEG_UC|ORTHO_MID_LC)
+
+  stats := p.Stats()
+  c.Check(stats.AbbrevTypes, Equals, 4)
+  c.Check(stats.Collocations, Equals, 1)
+  c.Check(stats.SentenceStarters, Equals, 1)
+  c.Check(stats.OrthoTypes, Equals, 3)
+  c.Check(stats.AbbrevsWithPeriods, Equals, 1)
+  c.Check(stats.AbbrevLengths, DeepEquals, []int{0, 0, 2, 1, 1})
+  c.Check(stats.OrthoFlags, DeepEquals, []SampleCount[OrthoContext]{
+    {ORTHO_MID_LC, 2},
+    {ORTHO_BEG_UC|ORTHO_MID_LC, 1},
+  })
+
+  c.Check(stats.String(), Equals, `abbrev_types: 4 (1 with periods)
+collocations: 1
+sentence_starters: 1
+ortho_context: 3
+  MID_LC                         2
+  BEG_UC|MID_LC                  1
+abbreviation lengths:
+    2 2
+    3 1
+    4 1
+`)
+}
+
+func (s *StatsSuite) TestEmptyStats(c *C) {
+  stats := new(LanguageParameters).Stats()
+  c.Check(stats.AbbrevTypes, Equals, 0)
+  c.Check(stats.OrthoFlags, HasLen, 0)
+}
+
+func (s *StatsSuite) TestCoverage(c *C) {
+  p := new(LanguageParameters)
+  p.SetOrthographicContext("the", ORTHO_BEG_UC|ORTHO_MID_LC)
+  p.SetOrthographicContext("dog", ORTHO_MID_LC)
+
+  r := Coverage(p, "The dog saw the cat. The cat ran, 15 times.")
+  c.Check(r.Types, Equals, 6)
+  c.Check(r.CoveredTypes, Equals, 2)
+  c.Check(r.Tokens, Equals, 9)
+  c.Check(r.CoveredTokens, Equals, 4)
+  c.Check(r.Uncovered[0], Equals, SampleCount[string]{"cat", 2})
+  c.Check(r.Uncovered, HasLen, 4)
+  c.Check(r.String(), Equals, "ortho context covers 2 of 6 types (33.3%) and 4 of 9 tokens (44.4%)")
+
+  c.Check(Coverage(p, "").TypeCoverage(), Equals, 0.0)
+
+  // an abbreviation keeps its period, as in the annotator
+  p.SaveAbbrevType("dr")
+  p.SetOrthographicContext("dr.", ORTHO_BEG_UC)
+  r = Coverage(p, "Dr. Who saw the dog.")
+  c.Check(r.CoveredTypes, Equals, 3)
+  c.Check(r.Uncovered, DeepEquals, []SampleCount[string]{{"saw", 1}, {"who", 1}})
+
+  english := LoadLanguage("english")
+  c.Check(Coverage(english.Compact(), pruneSample), DeepEquals, Coverage(english, pruneSample))
+}
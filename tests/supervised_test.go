@@ -0,0 +1,93 @@
+package punkt
+
+import (
+  "strings"
+
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type SupervisedSuite struct{}
+
+var supervisedSuite = Suite(&SupervisedSuite{})
+
+const goldText = `Dr. Smith met the team on Jan. 5 at noon.
+He saw Mr. Jones at the club.
+
+The gol was late.
+He scored a gol.
+They said "stop."
+It was fine.
+`
+
+func (s *SupervisedSuite) TestAbbreviations(c *C) {
+  trainer := new(Trainer)
+  p := trainer.TrainWithSegmentedText(strings.Repeat(goldText, 6))
+
+  c.Check(p.AbbrevTypes, DeepEquals, map[string]bool{"dr": true, "jan": true, "mr": true})
+
+  jan, found := trainer.Report().Find(CANDIDATE_ABBREV_TYPE, "jan", "")
+  c.Assert(found, Equals, true)
+  c.Check(jan.Accepted, Equals, true)
+  c.Check(jan.Reason, Equals, REASON_GOLD)
+  c.Check(jan.Count, Equals, 6)
+  c.Check(jan.Score, Equals, 1.0)
+
+  // only seen with a period at the end of a sentence
+  _, found = trainer.Report().Find(CANDIDATE_ABBREV_TYPE, "gol", "")
+  c.Check(found, Equals, false)
+  _, found = trainer.Report().Find(CANDIDATE_ABBREV_TYPE, "stop", "")
+  c.Check(found, Equals, false)
+}
+
+// a sentence that was not split doesn't make an abbreviation of its last word
+func (s *SupervisedSuite) TestMissedBoundary(c *C) {
+  gold := strings.Repeat("He went home.\nIt was late.\n", 3) + "He went home. It was late.\nHe met Dr. Smith.\n"
+
+  trainer := new(Trainer)
+  p := trainer.TrainWithSegmentedText(gold)
+  c.Check(p.AbbrevTypes, DeepEquals, map[string]bool{"dr": true})
+
+  home, found := trainer.Report().Find(CANDIDATE_ABBREV_TYPE, "home", "")
+  c.Assert(found, Equals, true)
+  c.Check(home.Accepted, Equals, false)
+  c.Check(home.Reason, Equals, REASON_BELOW_CUTOFF)
+  c.Check(home.Count, Equals, 1)
+  c.Check(home.Score, Equals, 0.25)
+  c.Check(home.Cutoff, Equals, GOLD_ABBREV_CUTOFF)
+}
+
+func (s *SupervisedSuite) TestStartersAndCollocations(c *C) {
+  trainer := new(Trainer)
+  p := trainer.TrainWithSegmentedText(strings.Repeat(goldText, 6))
+
+  c.Check(trainer.SentenceBreakCount, Equals, 36)
+  c.Check(trainer.SentenceStarterFdist.Get("he"), Equals, 12)
+  c.Check(p.HasSentenceStarter("he"), Equals, true)
+  c.Check(p.HasSentenceStarter("smith"), Equals, false)
+  c.Check(p.HasCollocation("mr", "jones"), Equals, true)
+}
+
+func (s *SupervisedSuite) TestOrthographicContext(c *C) {
+  p := new(Trainer).TrainWithSentences([]string{"The cat sat.", "He saw the cat.", "They said \"stop.\""})
+
+  c.Check(p.GetOrthographicContext("the"), Equals, ORTHO_BEG_UC|ORTHO_MID_LC)
+  c.Check(p.GetOrthographicContext("cat"), Equals, ORTHO_MID_LC)
+  c.Check(p.GetOrthographicContext("stop"), Equals, ORTHO_MID_LC)
+
+  for k, v := range p.OrthographicContext {
+    c.Check(v & (ORTHO_UNK_UC|ORTHO_UNK_LC), Equals, OrthoContext(0), Commentf("%s", k))
+  }
+}
+
+func (s *SupervisedSuite) TestSameShape(c *C) {
+  trainer := new(Trainer)
+  p := trainer.TrainWithSegmentedText(strings.Repeat(goldText, 6))
+
+  c.Check(p.Validate(), IsNil)
+  c.Check(p.Metadata.TokenCount, Equals, trainer.TypeFdist.N)
+
+  tokenizer := new(Tokenizer)
+  tokenizer.SetParameters(p)
+  c.Check(tokenizer.SentencesFromText("He met Dr. Smith at noon. It was fine."), DeepEquals, []string{"He met Dr. Smith at noon.", "It was fine."})
+}
@@ -29,6 +29,15 @@ func (s *TokenSuite) TestFlags(c *C) {
   c.Check(token.IsAbbr(), Equals, false)
 }
 
+func (s *TokenSuite) TestClearingUnsetFlag(c *C) {
+  token := punkt.MakeToken("Test")
+
+  token.SetParagraphStart(false)
+  token.SetLineStart(false)
+  token.SetSentenceBreak(false)
+  c.Check(token.Flags, Equals, punkt.TokenFlags(0))
+}
+
 func (s *TokenSuite) TestTypeAttributes(c *C) {
   token := punkt.MakeToken("Test")
   c.Check(token.Type, Equals, "test")
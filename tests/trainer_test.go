@@ -1,7 +1,11 @@
 package punkt
 
 import (
-  _ "github.com/harrisj/punkt"
+  "bytes"
+  "strings"
+  "time"
+
+  "github.com/harrisj/punkt"
   . "gopkg.in/check.v1"
 )
 
@@ -12,6 +16,31 @@ var trainerSuite = Suite(&TrainerSuite{})
 func (s *TokenSuite) TestRareAbbrev(c *C) {
 }
 
+func (s *TrainerSuite) TestReclassifyAbbreviationTypes(c *C) {
+  trainer := new(punkt.Trainer)
+  trainer.TypeFdist.IncBy("dr.", 10)
+  trainer.TypeFdist.IncBy("the", 50)
+  trainer.TypeFdist.IncBy(",", 10)
+  trainer.PeriodTokensCount = 12
+
+  types := map[string]bool{"dr.": true, "the": true, ",": true, "##number##": true}
+  out := trainer.ReclassifyAbbreviationTypes(new(punkt.LanguageParameters), types)
+
+  // only the type with a final period is scored, without its period
+  c.Assert(out, HasLen, 1)
+  c.Check(out[0].Type, Equals, "dr")
+  c.Check(out[0].IsAdd, Equals, true)
+  c.Check(out[0].Score > punkt.ABBREV_CUTOFF, Equals, true, Commentf("score %v", out[0].Score))
+}
+
+func (s *TrainerSuite) TestSentenceStartersFollowBreaks(c *C) {
+  trainer := new(punkt.Trainer)
+  trainer.TrainWithText("The cat sat. Then it slept. Then it woke.")
+
+  c.Check(trainer.SentenceStarterFdist.Get("then"), Equals, 2)
+  c.Check(trainer.SentenceStarterFdist.N, Equals, 2)
+}
+
 // # encoding: utf-8
 // require File.expand_path(File.dirname(__FILE__) + '/../../test_helper')
 
@@ -43,4 +72,65 @@ func (s *TokenSuite) TestRareAbbrev(c *C) {
 //     assert !parameters.abbreviation_types.include?("gol")
 //   end
   
-// end
\ No newline at end of file
+// end
+func (s *TrainerSuite) TestTrainingMetadata(c *C) {
+  trainer := new(punkt.Trainer)
+  params := trainer.TrainWithText("The cat sat on the mat. The dog sat on the log.")
+
+  c.Check(params.Metadata.TokenCount, Equals, 12)
+  c.Check(params.Metadata.LibraryVersion, Equals, punkt.VERSION)
+  c.Check(*params.Metadata.TrainerSettings, Equals, punkt.DefaultTrainerSettings())
+  c.Check(params.Metadata.CreatedAt.IsZero(), Equals, false)
+}
+
+func (s *TrainerSuite) TestTrainingLearnsOrthoContext(c *C) {
+  params := new(punkt.Trainer).TrainWithText("The cat sat on the mat. The dog sat on the log.")
+
+  c.Check(params.GetOrthographicContext("the") & punkt.ORTHO_BEG_UC, Equals, punkt.ORTHO_BEG_UC)
+  c.Check(params.GetOrthographicContext("the") & punkt.ORTHO_MID_LC, Equals, punkt.ORTHO_MID_LC)
+  c.Check(params.GetOrthographicContext("dog"), Equals, punkt.ORTHO_MID_LC)
+}
+
+// Many types and pairs here are seen equally often, so any order taken from
+// a map shows up as a different report or model between runs
+const reproducibleText = "Dr. Smith met the gol. team. He saw Mr. Jones at the club. The gol. was late. Gol is a word. " +
+  "Ms. Brown and Mrs. Green left at noon. St. Louis is far. Prof. Grey and Gen. Lee spoke. Capt. Hook sailed. "
+
+func (s *TrainerSuite) TestTrainingIsReproducible(c *C) {
+  var model, mapped, report []byte
+  created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+  for i := 0; i < 10; i++ {
+    trainer := &punkt.Trainer{Now: func() time.Time { return created }}
+    params := trainer.TrainWithText(strings.Repeat(reproducibleText, 4))
+
+    m, err := params.ToJSON()
+    c.Assert(err, IsNil)
+
+    var mb, rb bytes.Buffer
+    c.Assert(punkt.WriteMappedParameters(&mb, params), IsNil)
+    c.Assert(trainer.Report().WriteJSON(&rb), IsNil)
+
+    if i == 0 {
+      model, mapped, report = m, mb.Bytes(), rb.Bytes()
+      c.Check(params.Metadata.CreatedAt, Equals, created)
+      continue
+    }
+
+    c.Check(bytes.Equal(m, model), Equals, true, Commentf("run %d:\n%s\n%s", i, m, model))
+    c.Check(bytes.Equal(mb.Bytes(), mapped), Equals, true, Commentf("run %d", i))
+    c.Check(bytes.Equal(rb.Bytes(), report), Equals, true, Commentf("run %d", i))
+  }
+}
+
+func (s *TrainerSuite) TestTrainingUsesLinesAndParagraphs(c *C) {
+  // "Apples" starts a paragraph, and "Figs" a line inside one, where it
+  // may or may not start a sentence
+  params := new(punkt.Trainer).TrainWithText("The cat sat.\n\nApples are red\nFigs are not.")
+
+  c.Check(params.GetOrthographicContext("apples"), Equals, punkt.ORTHO_BEG_UC)
+  c.Check(params.GetOrthographicContext("figs"), Equals, punkt.ORTHO_UNK_UC)
+
+  words := []string{"The", "cat", "sat", ".", punkt.PARAGRAPH_BREAK, "Apples", "are", "red", punkt.LINE_BREAK, "Figs", "are", "not", "."}
+  c.Check(new(punkt.Trainer).TrainWithTokenizedText(words).OrthographicContext, DeepEquals, params.OrthographicContext)
+}
@@ -0,0 +1,102 @@
+package punkt
+
+import (
+  "bytes"
+  "strings"
+
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type TuneSuite struct{}
+
+var tuneSuite = Suite(&TuneSuite{})
+
+func (s *TuneSuite) TestScoreSegmentation(c *C) {
+  gold := []string{"Dr. Smith went home.", "", "He slept.", "It was late."}
+
+  params := new(LanguageParameters)
+  score := ScoreSegmentation(params, gold)
+  c.Check(score, Equals, SegmentationScore{TruePositives: 2, FalsePositives: 1, FalseNegatives: 0})
+  c.Check(score.Precision(), Equals, 2.0/3.0)
+  c.Check(score.Recall(), Equals, 1.0)
+  c.Check(score.F1(), Equals, 0.8)
+
+  params.SaveAbbrevType("dr")
+  c.Check(ScoreSegmentation(params, gold), Equals, SegmentationScore{TruePositives: 2})
+  c.Check(ScoreSegmentation(params, gold).F1(), Equals, 1.0)
+}
+
+func (s *TuneSuite) TestGridSearch(c *C) {
+  var done []int
+
+  report, err := Tune(strings.Repeat(reportText, 5), strings.Split(goldText, "\n"), TuningOptions{
+    Space: TuningSpace{
+      AbbrevCutoffs: []float64{0.3, 1000},
+      AbbrevBackoffs: []int{1, 5},
+      SentStarterCutoffs: []float64{30},
+    },
+    Progress: func(n, total int, result TuningResult) {
+      c.Check(total, Equals, 4)
+      done = append(done, n)
+    },
+  })
+
+  c.Assert(err, IsNil)
+  c.Check(done, DeepEquals, []int{1, 2, 3, 4})
+  c.Assert(report.Results, HasLen, 4)
+
+  for i := 1; i < len(report.Results); i++ {
+    c.Check(report.Results[i-1].Score.F1() >= report.Results[i].Score.F1(), Equals, true)
+    c.Check(report.Results[i].Settings.CollocationCutoff, Equals, COLLOCATION_CUTOFF)
+  }
+
+  // dr is only learned with the lower cutoff
+  best := report.Best()
+  c.Check(best.Settings.AbbrevCutoff, Equals, 0.3)
+  c.Check(best.Score.F1() > report.Results[3].Score.F1(), Equals, true)
+  c.Check(report.Parameters.HasAbbrevType("dr"), Equals, true)
+  c.Check(report.String(), Matches, "best of 4: abbrev cutoff 0.3, .*")
+
+  // the model kept is the one of the best settings, the first tried on a tie
+  trainer := new(Trainer)
+  c.Assert(trainer.SetSettings(best.Settings), IsNil)
+  expected := trainer.TrainWithTokenizedText(SplitTextIntoMarkedWords(strings.Repeat(reportText, 5)))
+  c.Check(report.Parameters.Diff(expected).IsEmpty(), Equals, true)
+
+  var b bytes.Buffer
+  c.Assert(report.WriteCSV(&b), IsNil)
+  lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+  c.Assert(lines, HasLen, 5)
+  c.Check(lines[0], Equals, "abbrev_cutoff,abbrev_backoff,collocation_cutoff,sent_starter_cutoff,precision,recall,f1,true_positives,false_positives,false_negatives")
+  c.Check(lines[1], Matches, "0.3,[15],7.88,30,.*")
+}
+
+func (s *TuneSuite) TestRandomSearch(c *C) {
+  options := TuningOptions{
+    Space: TuningSpace{
+      AbbrevCutoffs: []float64{0.1, 0.3, 1},
+      CollocationCutoffs: []float64{5, 7.88, 10},
+    },
+    Samples: 4,
+    Seed: 7,
+  }
+
+  first, err := Tune(reportText, strings.Split(goldText, "\n"), options)
+  c.Assert(err, IsNil)
+  c.Check(first.Results, HasLen, 4)
+
+  second, err := Tune(reportText, strings.Split(goldText, "\n"), options)
+  c.Assert(err, IsNil)
+  c.Check(second.Results, DeepEquals, first.Results)
+}
+
+func (s *TuneSuite) TestErrors(c *C) {
+  _, err := Tune(reportText, []string{"", " "}, TuningOptions{})
+  c.Check(err, ErrorMatches, "punkt: no gold sentences to tune against")
+
+  base := DefaultTrainerSettings()
+  base.CollocationMeasure = "nonsense"
+  _, err = Tune(reportText, []string{"One."}, TuningOptions{Base: &base})
+  c.Check(err, ErrorMatches, `punkt: unknown association measure "nonsense"`)
+}
@@ -0,0 +1,105 @@
+package punkt
+
+import (
+  "io/ioutil"
+  "path/filepath"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type ValidateSuite struct{}
+
+var validateSuite = Suite(&ValidateSuite{})
+
+func fields(err error) []string {
+  out := []string{}
+  for _, e := range err.(ValidationErrors) {
+    out = append(out, e.Field)
+  }
+  return out
+}
+
+func (s *ValidateSuite) TestValidJSON(c *C) {
+  c.Check(ValidateJSON([]byte(`{"abbrev_types": ["dr"], "collocations": ["jan|15", ["feb", "12"]], "sentence_starters": null, "ortho_context": {"dog": 32}}`)), IsNil)
+}
+
+func (s *ValidateSuite) TestBundledModels(c *C) {
+  paths, err := filepath.Glob("../data/*.json")
+  c.Assert(err, IsNil)
+  c.Assert(paths, Not(HasLen), 0)
+
+  for _, path := range paths {
+    contents, err := ioutil.ReadFile(path)
+    c.Assert(err, IsNil)
+
+    p, err := LoadParametersFromJSONStrict(contents)
+    c.Check(err, IsNil, Commentf(path))
+    c.Check(p.Validate(), IsNil, Commentf(path))
+    c.Check(p.Collocations, Not(HasLen), 0, Commentf(path))
+  }
+}
+
+func (s *ValidateSuite) TestProblems(c *C) {
+  err := ValidateJSON([]byte(`{
+    "abbrev_type": ["dr"],
+    "abbrev_types": ["dr", "mr", "dr", ""],
+    "collocations": ["jan15", "|15", ["a", "b", "c"], 5],
+    "ortho_context": {"dog": -2, "cat": 4096, "cow": 0, "dog": 2, "pig": "x"},
+    "metadata": {"langauge": "en"}
+  }`))
+
+  c.Assert(err, NotNil)
+  c.Check(fields(err), DeepEquals, []string{
+    "abbrev_type",
+    "abbrev_types[2]",
+    "abbrev_types[3]",
+    "collocations[0]",
+    "collocations[1]",
+    "collocations[2]",
+    "collocations[3]",
+    `ortho_context["dog"]`,
+    `ortho_context["cat"]`,
+    `ortho_context["cow"]`,
+    `ortho_context["dog"]`,
+    `ortho_context["pig"]`,
+    "metadata",
+  })
+
+  errs := err.(ValidationErrors)
+  c.Check(errs[0].Message, Equals, `unknown key "abbrev_type", did you mean "abbrev_types"?`)
+  c.Check(errs[0].Offset, Equals, int64(6))
+  c.Check(errs[1].Message, Equals, `duplicate of abbrev_types[0] "dr"`)
+  c.Check(errs[3].Message, Equals, `collocation "jan15" is not two types separated by |`)
+  c.Check(errs[7].Message, Equals, "negative flags -2")
+  c.Check(errs[8].Message, Equals, "unknown flag bits 0x1000")
+
+  _, err = LoadParametersFromJSONStrict([]byte(`[]`))
+  c.Check(err, ErrorMatches, "(?s)punkt: 1 problems in model:.*must be a JSON object")
+}
+
+func (s *ValidateSuite) TestValidateParameters(c *C) {
+  p := new(LanguageParameters)
+  p.SaveAbbrevType("dr")
+  c.Check(p.Validate(), IsNil)
+
+  p.SaveAbbrevType("")
+  p.SaveCollocation("", "15")
+  p.SetOrthographicContext("dog", 0)
+  p.SetOrthographicContext("cat", 1 << 20)
+
+  c.Check(fields(p.Validate()), DeepEquals, []string{
+    `abbrev_types[""]`,
+    `collocations["|15"]`,
+    `ortho_context["cat"]`,
+    `ortho_context["dog"]`,
+  })
+}
+
+func (s *ValidateSuite) TestCollocationEscapes(c *C) {
+  c.Check(ValidateJSON([]byte(`{"collocations": ["a\\|b|c", ["x|y", "z"]]}`)), IsNil)
+
+  err := ValidateJSON([]byte(`{"collocations": ["a\\b|c", "a|b\\|c", ["a", "b|c"]]}`))
+  c.Check(fields(err), DeepEquals, []string{"collocations[0]", "collocations[2]"})
+  c.Check(err.(ValidationErrors)[1].Message, Equals, `duplicate of collocations[1] "a|b\\|c"`)
+}
+
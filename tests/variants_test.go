@@ -0,0 +1,129 @@
+package punkt
+
+import (
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  . "github.com/harrisj/punkt"
+  . "gopkg.in/check.v1"
+)
+
+type VariantsSuite struct{}
+
+var variantsSuite = Suite(&VariantsSuite{})
+
+func austrianGerman() Variant {
+  return Variant{
+    Name: "german-at",
+    Base: "german",
+    Tags: []string{"de-AT"},
+    Changes: ParametersDiff{
+      AbbrevTypesAdded: []string{"hofr", "mag"},
+      SentenceStartersAdded: []string{"grüß"},
+      CollocationsAdded: []Collocation{{"jän", "15"}},
+      OrthoChanged: []OrthoChange{{Type: "jänner", New: ORTHO_MID_LC}},
+    },
+  }
+}
+
+func (s *VariantsSuite) TestApply(c *C) {
+  base := LoadLanguage("german")
+  p, err := austrianGerman().Resolve()
+  c.Assert(err, IsNil)
+
+  c.Check(p.HasAbbrevType("hofr"), Equals, true)
+  c.Check(p.HasSentenceStarter("grüß"), Equals, true)
+  c.Check(p.HasCollocation("jän", "15"), Equals, true)
+  c.Check(p.GetOrthographicContext("jänner"), Equals, ORTHO_MID_LC)
+  c.Check(len(p.AbbrevTypes), Equals, len(base.AbbrevTypes) + 2)
+  c.Check(base.HasAbbrevType("hofr"), Equals, false)
+}
+
+func (s *VariantsSuite) TestDerive(c *C) {
+  p := LoadLanguage("portuguese")
+  p.SaveAbbrevType("dra")
+  p.SaveAbbrevType("prof.ª")
+  for k := range p.SentenceStarters {
+    delete(p.SentenceStarters, k)
+    break
+  }
+
+  v, err := DeriveVariant("portuguese-pt", "portuguese", p)
+  c.Assert(err, IsNil)
+  c.Check(v.Changes.SentenceStartersRemoved, HasLen, len(LoadLanguage("portuguese").SentenceStarters) - len(p.SentenceStarters))
+
+  resolved, err := v.Resolve()
+  c.Assert(err, IsNil)
+  c.Check(resolved.Diff(p).IsEmpty(), Equals, true)
+}
+
+func (s *VariantsSuite) TestDeltaFile(c *C) {
+  dir, err := ioutil.TempDir("", "punkt-variants")
+  c.Assert(err, IsNil)
+  defer os.RemoveAll(dir)
+
+  path := filepath.Join(dir, "german-at.json")
+  c.Assert(SaveVariantToJSON(austrianGerman(), path), IsNil)
+
+  loaded, err := LoadVariantFromFile(path)
+  c.Assert(err, IsNil)
+  c.Check(loaded, DeepEquals, austrianGerman())
+
+  expected, _ := austrianGerman().Resolve()
+  p, err := loaded.Resolve()
+  c.Assert(err, IsNil)
+  c.Check(p.Diff(expected).IsEmpty(), Equals, true)
+
+  _, err = LoadVariantFromJSON([]byte(`{"name": "x", "base": "german", "abbrevs_added": ["a"]}`))
+  c.Check(err, ErrorMatches, `punkt: bad variant: .*unknown field "abbrevs_added"`)
+
+  _, err = LoadVariantFromJSON([]byte(`{"name": "x"}`))
+  c.Check(err, ErrorMatches, `punkt: bad variant: name and base are required`)
+}
+
+func (s *VariantsSuite) TestRegister(c *C) {
+  c.Assert(RegisterVariant(austrianGerman()), IsNil)
+  defer UnregisterVariant("german-at")
+
+  name, err := ResolveLanguage("de-AT")
+  c.Check(err, IsNil)
+  c.Check(name, Equals, "german-at")
+
+  name, _ = ResolveLanguage("de-CH")
+  c.Check(name, Equals, "german")
+
+  t := new(Tokenizer)
+  c.Assert(t.SetLanguage("de_AT"), IsNil)
+  c.Check(t.Parameters().HasAbbrevType("hofr"), Equals, true)
+
+  // variants can build on variants
+  vienna := Variant{Name: "german-at-vienna", Base: "german-at", Changes: ParametersDiff{AbbrevTypesAdded: []string{"wr"}}}
+  c.Assert(RegisterVariant(vienna), IsNil)
+  defer UnregisterVariant("german-at-vienna")
+  p := LoadLanguage("german-at-vienna")
+  c.Check(p.HasAbbrevType("wr"), Equals, true)
+  c.Check(p.HasAbbrevType("hofr"), Equals, true)
+
+  loop := austrianGerman()
+  loop.Base = "german-at-vienna"
+  c.Check(RegisterVariant(loop), ErrorMatches, `punkt: variant "german-at" is based on itself`)
+
+  c.Check(RegisterVariant(Variant{Name: "german", Base: "english"}), ErrorMatches, ".*name of a bundled language")
+  c.Check(RegisterVariant(Variant{Name: "Swiss_German", Base: "german"}), ErrorMatches, ".*must be lower case.*")
+  c.Check(RegisterVariant(Variant{Name: "x", Base: "germn"}), ErrorMatches, `punkt: unknown language "germn", closest supported languages are german.*`)
+}
+
+func (s *VariantsSuite) TestUnregister(c *C) {
+  c.Assert(RegisterVariant(austrianGerman()), IsNil)
+  UnregisterVariant("german-at")
+
+  // both fall back to the base language again
+  for _, tag := range []string{"de-AT", "german-at"} {
+    name, err := ResolveLanguage(tag)
+    c.Check(err, IsNil)
+    c.Check(name, Equals, "german", Commentf(tag))
+  }
+
+  // unregistering again does nothing
+  UnregisterVariant("german-at")
+}
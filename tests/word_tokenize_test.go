@@ -1,6 +1,8 @@
 package punkt
 
 import (
+  "fmt"
+
   "github.com/harrisj/punkt"
   . "gopkg.in/check.v1"
 )
@@ -28,3 +30,28 @@ func (s *WordTokenizeSuite) TestWordTokenize(c *C) {
   tokens := punkt.SplitTextIntoWords(sentence)
   c.Check(tokens, DeepEquals, []string{"For", "example", ",", "the", "word", "\"", "abbreviation", "\"", "can", "itself", "be", "represented", "by", "the", "abbreviation", "abbr.", ",", "abbrv.", "or", "abbrev."})
 }
+
+func (s *WordTokenizeSuite) TestMarkedWords(c *C) {
+  c.Check(punkt.SplitTextIntoMarkedWords("apple pears\nfigs\n\nkiwi"), DeepEquals, []string{"apple", "pears", punkt.LINE_BREAK, "figs", punkt.LINE_BREAK, punkt.LINE_BREAK, "kiwi"})
+  c.Check(punkt.SplitTextIntoMarkedWords("apple pears"), DeepEquals, []string{"apple", "pears"})
+}
+
+func (s *WordTokenizeSuite) TestTokenizeTextStarts(c *C) {
+  var starts []string
+
+  for _, t := range punkt.TokenizeText("Apple pears\nFigs\n   \nKiwi plums") {
+    starts = append(starts, fmt.Sprintf("%s:%v:%v", t.Value, t.IsLineStart(), t.IsParagraphStart()))
+  }
+
+  c.Check(starts, DeepEquals, []string{"Apple:true:false", "pears:false:false", "Figs:true:false", "Kiwi:true:true", "plums:false:false"})
+}
+
+func (s *WordTokenizeSuite) TestMakeTokensFromMarkers(c *C) {
+  tokens := punkt.MakeTokens([]string{"Apple", punkt.PARAGRAPH_BREAK, "Figs", punkt.LINE_BREAK, "kiwi"})
+
+  c.Assert(tokens, HasLen, 3)
+  c.Check(tokens[0].IsLineStart(), Equals, true)
+  c.Check(tokens[1].IsParagraphStart(), Equals, true)
+  c.Check(tokens[2].IsLineStart(), Equals, true)
+  c.Check(tokens[2].IsParagraphStart(), Equals, false)
+}
@@ -39,7 +39,7 @@ func (t *Token) SetAbbr(b bool) {
   if b {
     t.Flags |= TOK_ABBR
   } else {
-    t.Flags ^= TOK_ABBR
+    t.Flags &^= TOK_ABBR
   }
 }
 
@@ -51,7 +51,7 @@ func (t *Token) SetSentenceBreak(b bool) {
   if b {
     t.Flags |= TOK_SENTENCE_BREAK
   } else {
-    t.Flags ^= TOK_SENTENCE_BREAK
+    t.Flags &^= TOK_SENTENCE_BREAK
   }
 }
 
@@ -63,7 +63,7 @@ func (t *Token) SetEllipsis(b bool) {
   if b {
     t.Flags |= TOK_ELLIPSIS
   } else {
-    t.Flags ^= TOK_ELLIPSIS
+    t.Flags &^= TOK_ELLIPSIS
   }
 }
 
@@ -75,7 +75,7 @@ func (t *Token) SetParagraphStart(b bool) {
   if b {
     t.Flags |= TOK_PARAGRAPH_START
   } else {
-    t.Flags ^= TOK_PARAGRAPH_START
+    t.Flags &^= TOK_PARAGRAPH_START
   }
 }
 
@@ -87,7 +87,7 @@ func (t *Token) SetLineStart(b bool) {
   if b {
     t.Flags |= TOK_LINE_START
   } else {
-    t.Flags ^= TOK_LINE_START
+    t.Flags &^= TOK_LINE_START
   }
 }
 
@@ -1,20 +1,54 @@
 package punkt
 
 import (
+  "fmt"
   "regexp"
 )
 
 type Tokenizer struct {
-  parameters *LanguageParameters
+  parameters ParameterSet
+  language string
 }
 
 func (t *Tokenizer) SetParameters(l *LanguageParameters) {
   t.parameters = l
 }
 
-// A shortcut to set the parameters for a specific language
-func (t *Tokenizer) SetLanguage(lang string) {
-  t.SetParameters(LoadLanguage(lang))
+// Use any ParameterSet, such as an Overlay, for finding sentence breaks
+func (t *Tokenizer) SetParameterSet(p ParameterSet) {
+  t.parameters = p
+}
+
+func (t Tokenizer) Parameters() ParameterSet {
+  return t.parameters
+}
+
+// A shortcut to set the parameters for a specific language. The language
+// can be a bundled language name or a tag like "en-US" (see ResolveLanguage).
+func (t *Tokenizer) SetLanguage(lang string) error {
+  name, err := ResolveLanguage(lang)
+  if err != nil {
+    return err
+  }
+
+  t.SetParameters(LoadLanguage(name))
+  t.language = name
+  return nil
+}
+
+// Adds a bundled domain abbreviation pack (see AbbrevPacks) for the language
+// set with SetLanguage. The packs are layered on the current parameters with
+// an Overlay, so the loaded model itself is not changed.
+func (t *Tokenizer) EnableAbbrevPack(domain string) error {
+  pack, found := FindAbbrevPack(domain, t.language)
+  if !found {
+    return fmt.Errorf("punkt: no %q abbreviation pack for language %q", domain, t.language)
+  }
+
+  o := NewOverlay(t.parameters)
+  pack.Apply(o)
+  t.parameters = o
+  return nil
 }
 
 func (t Tokenizer) SentencesFromText(text string) (sentences []string) {
@@ -1,10 +1,12 @@
 package punkt
 
 import (
+  "context"
   "regexp"
   "strings"
   "fmt"
   "math"
+  "time"
 )
 
 const (
@@ -44,12 +46,30 @@ const (
 )
 
 type Trainer struct {
-  TypeFdist            FrequencyDistribution
-  CollocationFdist     FrequencyDistribution
-  SentenceStarterFdist FrequencyDistribution
+  TypeFdist            FrequencyDistribution[string]
+  CollocationFdist     FrequencyDistribution[Collocation]
+  SentenceStarterFdist FrequencyDistribution[string]
   PeriodTokensCount    int
   SentenceBreakCount   int
   Finalized            bool
+
+  // types seen with a final period inside gold sentences and at their end,
+  // by TrainWithSentences
+  GoldAbbrevFdist      FrequencyDistribution[string]
+  GoldFinalFdist       FrequencyDistribution[string]
+
+  // Entries taken as given instead of learned. AbbrevTypes are always
+  // abbreviations and NonAbbrevTypes never are, and SentenceStarters and
+  // Collocations are always in the model. The Base is not used.
+  Seeds                *Overlay
+
+  // The clock for the CreatedAt of trained models, time.Now if nil. Fix it
+  // to get the same model file from the same corpus.
+  Now                  func() time.Time
+
+  report TrainingReport
+  settings *TrainerSettings
+  abbrevMeasure, starterMeasure, collocationMeasure AssociationMeasure
 }
 
 type AbbrevClassification struct {
@@ -58,27 +78,103 @@ type AbbrevClassification struct {
   IsAdd bool
 }
 
+// Changes the settings used from now on. Empty measure names mean the
+// default measures.
+func (t *Trainer) SetSettings(s TrainerSettings) error {
+  defaults := DefaultTrainerSettings()
+  measures := make([]AssociationMeasure, 3)
+
+  for i, name := range []*string{&s.AbbrevMeasure, &s.SentStarterMeasure, &s.CollocationMeasure} {
+    if *name == "" {
+      *name = []string{defaults.AbbrevMeasure, defaults.SentStarterMeasure, defaults.CollocationMeasure}[i]
+    }
+
+    m, err := FindAssociationMeasure(*name)
+    if err != nil {
+      return err
+    }
+
+    measures[i] = m
+  }
+
+  t.settings = &s
+  t.abbrevMeasure, t.starterMeasure, t.collocationMeasure = measures[0], measures[1], measures[2]
+  return nil
+}
+
+// The settings in use, DefaultTrainerSettings unless SetSettings was called
+func (t *Trainer) Settings() TrainerSettings {
+  if t.settings == nil {
+    return DefaultTrainerSettings()
+  }
+
+  return *t.settings
+}
+
+func (t *Trainer) measures() (abbrev, starter, collocation AssociationMeasure) {
+  if t.settings == nil {
+    abbrev, _ = FindAssociationMeasure(MEASURE_DUNNING)
+    starter, _ = FindAssociationMeasure(MEASURE_LOG_LIKELIHOOD)
+    return abbrev, starter, starter
+  }
+
+  return t.abbrevMeasure, t.starterMeasure, t.collocationMeasure
+}
+
 func (t *Trainer) TrainWithText(text string) *LanguageParameters {
-  tokens := SplitTextIntoWords(text)
-  return t.TrainWithTokenizedText(tokens)
+  params, _ := t.TrainWithTextContext(context.Background(), text, nil)
+  return params
 }
 
 func (t *Trainer) TrainWithTokenizedText(textTokens []string) *LanguageParameters {
-  tokens := make([]*Token, len(textTokens))
-  for i := range textTokens {
-    tokens[i] = MakeToken(textTokens[i])
+  params, _ := t.TrainWithTokenizedTextContext(context.Background(), textTokens, nil)
+  return params
+}
+
+// Like TrainWithText, but stops with a *TrainingCanceledError when ctx is
+// done, and calls progress (if not nil) as it goes
+func (t *Trainer) TrainWithTextContext(ctx context.Context, text string, progress func(TrainingProgress)) (*LanguageParameters, error) {
+  if err := ctx.Err(); err != nil {
+    return nil, &TrainingCanceledError{PHASE_COUNTING, 0, err}
   }
 
-  params := t.trainFromTokens(tokens)
-  return params
+  return t.TrainWithTokenizedTextContext(ctx, SplitTextIntoMarkedWords(text), progress)
+}
+
+// The words may have LINE_BREAK and PARAGRAPH_BREAK markers between them,
+// as in SplitTextIntoMarkedWords, so the orthographic contexts are learned
+// with the lines and paragraphs of the text
+func (t *Trainer) TrainWithTokenizedTextContext(ctx context.Context, textTokens []string, progress func(TrainingProgress)) (*LanguageParameters, error) {
+  return t.runTraining(ctx, progress, func(r *trainingRun) (*LanguageParameters, error) {
+    return t.trainFromTokens(r, textTokens)
+  })
 }
 
 // private methods
-func (t *Trainer) trainFromTokens(tokens []*Token) *LanguageParameters {
+func (t *Trainer) trainFromTokens(r *trainingRun, textTokens []string) (*LanguageParameters, error) {
   uniqueTypes := map[string]bool{}
   parameters := new(LanguageParameters)
+  r.use(parameters)
+
+  if err := r.start(PHASE_COUNTING, len(textTokens)); err != nil {
+    return nil, err
+  }
+
+  marks := newLineMarks()
+  tokens := make([]*Token, 0, len(textTokens))
+  for i := range textTokens {
+    if err := r.step(i); err != nil {
+      return nil, err
+    }
+
+    if marks.skip(textTokens[i]) {
+      continue
+    }
+
+    tok := MakeToken(textTokens[i])
+    marks.mark(tok)
+    tokens = append(tokens, tok)
 
-  for _, tok := range tokens {
     t.TypeFdist.Inc(tok.Type)
     uniqueTypes[tok.Type] = true
 
@@ -87,24 +183,54 @@ func (t *Trainer) trainFromTokens(tokens []*Token) *LanguageParameters {
     }
   }
 
+  if err := r.finish(); err != nil {
+    return nil, err
+  }
+
+  t.report = TrainingReport{}
+  abbrevCandidates := map[string]int{}
+  t.seedAbbrevTypes(parameters)
+  cutoff := t.Settings().AbbrevCutoff
+
   // reclassify abbeviation types
-  abbr_types := t.ReclassifyAbbreviationTypes(parameters, uniqueTypes)
+  abbr_types, err := t.reclassifyAbbreviationTypes(r, parameters, uniqueTypes)
+  if err != nil {
+    return nil, err
+  }
 
   for _, ac := range abbr_types {
-    if ac.Score >= ABBREV_CUTOFF {
-      if ac.IsAdd {
-        parameters.SaveAbbrevType(ac.Type)
-      }  
+    // "dr" and "dr." give the same candidate
+    if _, found := abbrevCandidates[ac.Type]; found {
+      continue
+    }
+
+    c := t.abbrevCandidate(ac.Type, ac.Score, cutoff)
+    c.Accepted, c.Reason = ac.Score >= cutoff, REASON_SCORE
+
+    if !c.Accepted {
+      c.Reason = REASON_BELOW_CUTOFF
+    }
+
+    t.applySeeds(&c)
+
+    if c.Accepted {
+      parameters.SaveAbbrevType(ac.Type)
     } else {
-      if !(ac.IsAdd) {
-        parameters.DeleteAbbrevType(ac.Type)
-      }
+      parameters.DeleteAbbrevType(ac.Type)
     }
+
+    t.addAbbrevCandidate(abbrevCandidates, c)
+  }
+
+  if err := r.finish(); err != nil {
+    return nil, err
   }
 
   tokens = AnnotateFirstPass(parameters, tokens)
 
-  t.BuildOrthographyTables(parameters, tokens)
+  if err := t.buildOrthographyTables(r, parameters, tokens); err != nil {
+    return nil, err
+  }
 
   for _, tok := range tokens {
     if tok.IsSentenceBreak() {
@@ -112,8 +238,16 @@ func (t *Trainer) trainFromTokens(tokens []*Token) *LanguageParameters {
     }
   }
 
+  if err := r.start(PHASE_CANDIDATES, len(tokens)); err != nil {
+    return nil, err
+  }
+
   // in pairs
   for i, tok2 := range tokens {
+    if err := r.step(i); err != nil {
+      return nil, err
+    }
+
     if i == 0 {
       continue
     }
@@ -124,31 +258,82 @@ func (t *Trainer) trainFromTokens(tokens []*Token) *LanguageParameters {
       continue
     }
 
-    if t.IsRareAbbrevType(parameters, tok1, tok2) {
-      parameters.SaveAbbrevType(tok1.TypeWithoutPeriod())
+    if t.IsRareAbbrevType(parameters, tok1, tok2) && !t.forbiddenAbbrevType(tok1.TypeWithoutPeriod()) {
+      tType := tok1.TypeWithoutPeriod()
+      parameters.SaveAbbrevType(tType)
+
+      c := t.abbrevCandidate(tType, 0, cutoff)
+      if i, found := abbrevCandidates[tType]; found {
+        c = t.report.Candidates[i]
+      }
+
+      c.Accepted, c.Reason = true, REASON_RARE_ABBREV
+      t.addAbbrevCandidate(abbrevCandidates, c)
     }
 
-    if t.IsPotentialSentenceStarter(tok1, tok2) {
+    if t.IsPotentialSentenceStarter(tok2, tok1) {
       t.SentenceStarterFdist.Inc(tok2.Type)
     }
 
     if t.IsPotentialCollocation(tok1, tok2) {
-      colloc_key := collocationMapKey(tok1.TypeWithoutPeriod(), tok2.TypeWithoutPeriod())
-      t.CollocationFdist.Inc(colloc_key)
+      t.CollocationFdist.Inc(Collocation{tok1.TypeWithoutPeriod(), tok2.TypeWithoutPeriod()})
     }
   }
 
-  t.FinalizeTraining(parameters)
-  return parameters
+  if err := r.finish(); err != nil {
+    return nil, err
+  }
+
+  t.addSeededAbbrevCandidates(abbrevCandidates)
+  if err := t.finalizeTraining(r, parameters); err != nil {
+    return nil, err
+  }
+
+  parameters.Metadata = t.trainingMetadata()
+  return parameters, nil
 }
 
-func (t *Trainer) ReclassifyAbbreviationTypes(parameters *LanguageParameters, uniqueTypes map[string]bool) (out []AbbrevClassification) {
+func (t *Trainer) trainingMetadata() ModelMetadata {
+  settings := t.Settings()
+  now := time.Now
+
+  if t.Now != nil {
+    now = t.Now
+  }
+
+  return ModelMetadata{
+    TokenCount: t.TypeFdist.N,
+    TrainerSettings: &settings,
+    LibraryVersion: VERSION,
+    CreatedAt: now().UTC(),
+  }
+}
+
+func (t *Trainer) ReclassifyAbbreviationTypes(parameters *LanguageParameters, uniqueTypes map[string]bool) []AbbrevClassification {
+  out, _ := t.reclassifyAbbreviationTypes(nil, parameters, uniqueTypes)
+  return out
+}
+
+func (t *Trainer) reclassifyAbbreviationTypes(r *trainingRun, parameters *LanguageParameters, uniqueTypes map[string]bool) (out []AbbrevClassification, err error) {
   punctRegexp := regexp.MustCompile("[^\\W\\d]")
   isAdd := false
+  settings := t.Settings()
+  measure, _, _ := t.measures()
+  processed := 0
+
+  if err := r.start(PHASE_ABBREVIATIONS, len(uniqueTypes)); err != nil {
+    return nil, err
+  }
+
+  // in order, as the first of "dr" and "dr." wins
+  for _, key := range sortedKeys(uniqueTypes) {
+    if err := r.step(processed); err != nil {
+      return nil, err
+    }
+    processed++
 
-  for key, _ := range uniqueTypes {
     // if there is punctuation or is a number, continue. This will be processed later
-    if key == "##number##" || punctRegexp.MatchString(key) {
+    if key == "##number##" || !punctRegexp.MatchString(key) {
       continue
     }
 
@@ -157,7 +342,7 @@ func (t *Trainer) ReclassifyAbbreviationTypes(parameters *LanguageParameters, un
         continue
       }
 
-      key = key[0:len(key)]  // chop
+      key = key[0:len(key)-1]  // chop
       isAdd = true
     } else {
       if !(parameters.HasAbbrevType(key)) {
@@ -173,13 +358,13 @@ func (t *Trainer) ReclassifyAbbreviationTypes(parameters *LanguageParameters, un
     withPeriodsCount := t.TypeFdist.Get(fmt.Sprintf("%s.", key))
     withoutPeriodsCount := t.TypeFdist.Get(key)
 
-    ll := DunningLogLikelihood(withPeriodsCount + withoutPeriodsCount, t.PeriodTokensCount, withPeriodsCount, t.TypeFdist.N)
+    ll := measure.Score(withPeriodsCount + withoutPeriodsCount, t.PeriodTokensCount, withPeriodsCount, t.TypeFdist.N)
 
     fLength  := math.Exp(float64(-nonPeriodsCount))
     fPeriods := periodsCount
-    fPenalty := float64(0)
+    fPenalty := float64(1)
 
-    if !(IGNORE_ABBREV_PENALTY) {
+    if !(settings.IgnoreAbbrevPenalty) {
       fPenalty = math.Pow(float64(nonPeriodsCount), float64(-withoutPeriodsCount))
     }
 
@@ -188,7 +373,7 @@ func (t *Trainer) ReclassifyAbbreviationTypes(parameters *LanguageParameters, un
     out = append(out, AbbrevClassification{key, score, isAdd})
   }
 
-  return out
+  return out, nil
 }
 
 func DunningLogLikelihood(count_a, count_b, count_ab, n int) float64 {
@@ -212,7 +397,7 @@ func ColLogLikelihood(count_a, count_b, count_ab, n int) float64 {
 
   summand3 := float64(0)
   if count_a != count_ab {
-    summand3 = float64(count_ab) * math.Log(p1) * float64(count_a - count_ab) * math.Log(1.0 - p1)
+    summand3 = float64(count_ab) * math.Log(p1) + float64(count_a - count_ab) * math.Log(1.0 - p1)
   }
 
   summand4 := float64(0)
@@ -233,7 +418,7 @@ func (t *Trainer) IsRareAbbrevType(parameters *LanguageParameters, current *Toke
   tType := current.TypeWithoutSentencePeriod()
   count := t.TypeFdist.Get(tType) + t.TypeFdist.Get(tType[0:len(tType)])
 
-  if parameters.HasAbbrevType(tType) || count >= ABBREV_BACKOFF {
+  if parameters.HasAbbrevType(tType) || count >= t.Settings().AbbrevBackoff {
     return false
   }
 
@@ -256,8 +441,10 @@ func (t *Trainer) IsPotentialSentenceStarter(current, prev *Token) bool {
 }
 
 func (t *Trainer) IsPotentialCollocation(tok1, tok2 *Token) bool {
-  return (INCLUDE_ALL_COLLOCS ||
-           (INCLUDE_ABBREV_COLLOCS && tok1.IsAbbr()) ||
+  settings := t.Settings()
+
+  return (settings.IncludeAllCollocs ||
+           (settings.IncludeAbbrevCollocs && tok1.IsAbbr()) ||
               (tok1.IsSentenceBreak() &&
                 (tok1.MatchNumber() || tok2.MatchInitial()))) &&
           tok1.MatchNonPunctuation() &&
@@ -265,9 +452,21 @@ func (t *Trainer) IsPotentialCollocation(tok1, tok2 *Token) bool {
 }
 
 func (t *Trainer) BuildOrthographyTables(parameters *LanguageParameters, tokens []*Token) {
+  t.buildOrthographyTables(nil, parameters, tokens)
+}
+
+func (t *Trainer) buildOrthographyTables(r *trainingRun, parameters *LanguageParameters, tokens []*Token) error {
   context := "internal"
 
-  for _, tok := range tokens {
+  if err := r.start(PHASE_ORTHOGRAPHY, len(tokens)); err != nil {
+    return err
+  }
+
+  for i, tok := range tokens {
+    if err := r.step(i); err != nil {
+      return err
+    }
+
     if tok.IsParagraphStart() && context != "unknown" {
       context = "initial"
     }
@@ -277,8 +476,7 @@ func (t *Trainer) BuildOrthographyTables(parameters *LanguageParameters, tokens
     }
 
     tType := tok.TypeWithoutSentencePeriod()
-    orthoKey := fmt.Sprintf("%s|%s", tType, tok.FirstCase())
-    flag := ORTHO_MAP[orthoKey]
+    flag := orthoFlag(context, tok)
 
     if flag > 0 {
       parameters.AddOrthographicContext(tType, flag)
@@ -296,6 +494,8 @@ func (t *Trainer) BuildOrthographyTables(parameters *LanguageParameters, tokens
       context = "internal"
     }
   }
+
+  return r.finish()
 }
 
 type foundSentenceStarter struct {
@@ -310,76 +510,216 @@ type foundCollocation struct {
 }
 
 func (t *Trainer) FinalizeTraining(parameters *LanguageParameters) {
+  t.finalizeTraining(nil, parameters)
+}
+
+func (t *Trainer) finalizeTraining(r *trainingRun, parameters *LanguageParameters) error {
+  if err := r.start(PHASE_FINALIZE, len(t.SentenceStarterFdist.Counts) + len(t.CollocationFdist.Counts)); err != nil {
+    return err
+  }
+
+  scoredStarters, err := t.scoreSentenceStarters(r)
+  if err != nil {
+    return err
+  }
+
+  // keep the abbreviation candidates if this follows training
+  t.report.Candidates = t.report.OfKind(CANDIDATE_ABBREV_TYPE)
+
   parameters.ClearSentenceStarters()
-  a := t.FindSentenceStarters(parameters) 
+  starters := t.seedSentenceStarters(scoredStarters)
 
-  for _, x := range a {
-    parameters.SaveSentenceStarter(x.Type1)
+  for _, c := range starters {
+    if c.Accepted {
+      parameters.SaveSentenceStarter(c.Type)
+    }
+  }
+
+  scoredCollocations, err := t.scoreCollocations(r, parameters, len(t.SentenceStarterFdist.Counts))
+  if err != nil {
+    return err
   }
 
   parameters.ClearCollocations()
-  b := t.FindCollocations(parameters)
+  collocations := t.seedCollocations(scoredCollocations)
 
-  for _, y := range b {
-    parameters.SaveCollocation(y.Type1, y.Type2)
+  for _, c := range collocations {
+    if c.Accepted {
+      parameters.SaveCollocation(c.Type, c.Type2)
+    }
   }
-      
+
+  t.report.Candidates = append(t.report.Candidates, starters...)
+  t.report.Candidates = append(t.report.Candidates, collocations...)
+  t.report.TokenCount = t.TypeFdist.N
+  t.report.PeriodTokensCount = t.PeriodTokensCount
+  t.report.SentenceBreakCount = t.SentenceBreakCount
+  t.report.Sort("kind")
+
   t.Finalized = true
+  return r.finish()
+}
+
+// Every candidate scored by the last training run, with its decision
+func (t *Trainer) Report() TrainingReport {
+  r := t.report
+  r.Candidates = append([]TrainingCandidate(nil), r.Candidates...)
+  return r
 }
 
 func (t *Trainer) FindSentenceStarters(parameters *LanguageParameters) []foundSentenceStarter {
-  samples := t.SentenceStarterFdist.OrderedSamples()
   out := make([]foundSentenceStarter, 0)
 
-  for _, cs := range samples {
+  starters, _ := t.scoreSentenceStarters(nil)
+
+  for _, c := range starters {
+    if c.Accepted {
+      out = append(out, foundSentenceStarter{c.Type, c.Score})
+    }
+  }
+
+  return out
+}
+
+func (t *Trainer) scoreSentenceStarters(r *trainingRun) (out []TrainingCandidate, err error) {
+  samples := t.SentenceStarterFdist.OrderedSamples()
+  cutoff := t.Settings().SentStarterCutoff
+
+  for i, cs := range samples {
+    if err := r.step(i); err != nil {
+      return nil, err
+    }
+
     if len(cs.Sample) == 0 {
       continue
     }
 
-    typeCount := t.TypeFdist.Get(cs.Sample) + t.TypeFdist.Get(fmt.Sprintf("%v.", cs.Sample))
+    typeCount := t.typeCount(cs.Sample)
 
     if typeCount < cs.Count {
       continue
     }
 
-    ll := ColLogLikelihood(t.SentenceBreakCount, typeCount, cs.Count, t.TypeFdist.N)
+    ll := t.SentenceStarterScore(cs.Sample)
+    c := TrainingCandidate{
+      Kind: CANDIDATE_SENTENCE_STARTER,
+      Type: cs.Sample,
+      Count: cs.Count,
+      TypeCount: typeCount,
+      Score: ll,
+      Cutoff: cutoff,
+      Reason: REASON_BELOW_CUTOFF,
+    }
 
-    if ll >= SENT_STARTER_CUTOFF && ((float64(t.TypeFdist.N) / float64(t.SentenceBreakCount)) > (float64(typeCount) / float64(cs.Count))) {
-      out = append(out, foundSentenceStarter{cs.Sample, ll})
+    if ll >= cutoff {
+      if (float64(t.TypeFdist.N) / float64(t.SentenceBreakCount)) > (float64(typeCount) / float64(cs.Count)) {
+        c.Accepted, c.Reason = true, REASON_SCORE
+      } else {
+        c.Reason = REASON_RATIO
+      }
     }
+
+    out = append(out, c)
   }
 
-  return out
+  return out, nil
+}
+
+// count of a type with and without a final period
+func (t *Trainer) typeCount(tType string) int {
+  return t.TypeFdist.Get(tType) + t.TypeFdist.Get(fmt.Sprintf("%v.", tType))
+}
+
+// How strongly a type is associated with following a sentence break, by the
+// sentence starter measure (log-likelihood by default). Zero if the trainer
+// has not seen it after a break.
+func (t *Trainer) SentenceStarterScore(tType string) float64 {
+  count := t.SentenceStarterFdist.Get(tType)
+  typeCount := t.typeCount(tType)
+
+  if count == 0 || typeCount < count {
+    return 0
+  }
+
+  _, measure, _ := t.measures()
+  return measure.Score(t.SentenceBreakCount, typeCount, count, t.TypeFdist.N)
+}
+
+// How strongly two types are associated, by the collocation measure
+// (log-likelihood by default). Zero if the trainer has not seen the pair.
+func (t *Trainer) CollocationScore(type1, type2 string) float64 {
+  count := t.CollocationFdist.Get(Collocation{type1, type2})
+  type1Count := t.typeCount(type1)
+  type2Count := t.typeCount(type2)
+
+  if count == 0 || count > type1Count || count > type2Count {
+    return 0
+  }
+
+  _, _, measure := t.measures()
+  return measure.Score(type1Count, type2Count, count, t.TypeFdist.N)
 }
 
 func (t *Trainer) FindCollocations(parameters *LanguageParameters) []foundCollocation {
-  samples := t.CollocationFdist.OrderedSamples()
   out := make([]foundCollocation, 0)
 
-  for _, cs := range samples {
-    type1, type2 := collocationSplitKey(cs.Sample)
+  collocations, _ := t.scoreCollocations(nil, parameters, 0)
 
-    if len(type1) == 0 || len(type2) == 0 {
-      continue
+  for _, c := range collocations {
+    if c.Accepted {
+      out = append(out, foundCollocation{Type1: c.Type, Type2: c.Type2, Score: c.Score})
     }
+  }
 
-    if parameters.HasSentenceStarter(type2) {
-      continue
+  return out
+}
+
+// processed is the number of candidates already scored in this phase
+func (t *Trainer) scoreCollocations(r *trainingRun, parameters *LanguageParameters, processed int) (out []TrainingCandidate, err error) {
+  samples := t.CollocationFdist.OrderedSamples()
+  settings := t.Settings()
+
+  for i, cs := range samples {
+    if err := r.step(processed + i); err != nil {
+      return nil, err
     }
 
-    type1Count := t.TypeFdist.Get(type1) + t.TypeFdist.Get(fmt.Sprintf("%v.", type1))
-    type2Count := t.TypeFdist.Get(type2) + t.TypeFdist.Get(fmt.Sprintf("%v.", type2))
+    type1, type2 := cs.Sample.Type1, cs.Sample.Type2
 
-    if type1Count > 1 && type2Count > 1 && cs.Count > MIN_COLLOC_FREQ && cs.Count <= type1Count && cs.Count <= type2Count {
-      ll := ColLogLikelihood(type1Count, type2Count, cs.Count, t.TypeFdist.N)
+    if len(type1) == 0 || len(type2) == 0 {
+      continue
+    }
 
-      if ll >= COLLOCATION_CUTOFF && ((float64(t.TypeFdist.N)/float64(type1Count)) > (float64(type2Count)/float64(cs.Count))) {
-        out = append(out, foundCollocation{Type1: type1, Type2: type2, Score: ll})
-      }
+    type1Count := t.typeCount(type1)
+    type2Count := t.typeCount(type2)
+
+    c := TrainingCandidate{
+      Kind: CANDIDATE_COLLOCATION,
+      Type: type1,
+      Type2: type2,
+      Count: cs.Count,
+      TypeCount: type1Count,
+      Type2Count: type2Count,
+      Score: t.CollocationScore(type1, type2),
+      Cutoff: settings.CollocationCutoff,
     }
+
+    if parameters.HasSentenceStarter(type2) {
+      c.Reason = REASON_STARTER
+    } else if !(type1Count > 1 && type2Count > 1 && cs.Count > settings.MinCollocFreq && cs.Count <= type1Count && cs.Count <= type2Count) {
+      c.Reason = REASON_MIN_FREQ
+    } else if c.Score < settings.CollocationCutoff {
+      c.Reason = REASON_BELOW_CUTOFF
+    } else if (float64(t.TypeFdist.N)/float64(type1Count)) > (float64(type2Count)/float64(cs.Count)) {
+      c.Accepted, c.Reason = true, REASON_SCORE
+    } else {
+      c.Reason = REASON_RATIO
+    }
+
+    out = append(out, c)
   }
 
-  return out
+  return out, nil
 }
     
 //     def train(text_or_tokens)
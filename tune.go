@@ -0,0 +1,293 @@
+package punkt
+
+import (
+  "context"
+  "encoding/csv"
+  "errors"
+  "fmt"
+  "io"
+  "math/rand"
+  "sort"
+  "strconv"
+  "strings"
+)
+
+// How well a model finds the boundaries between gold sentences. Only the
+// boundaries between sentences count, not the end of the text.
+type SegmentationScore struct {
+  TruePositives int `json:"true_positives"`
+  FalsePositives int `json:"false_positives"`
+  FalseNegatives int `json:"false_negatives"`
+}
+
+func (s SegmentationScore) Precision() float64 {
+  if s.TruePositives + s.FalsePositives == 0 {
+    return 0
+  }
+
+  return float64(s.TruePositives) / float64(s.TruePositives + s.FalsePositives)
+}
+
+func (s SegmentationScore) Recall() float64 {
+  if s.TruePositives + s.FalseNegatives == 0 {
+    return 0
+  }
+
+  return float64(s.TruePositives) / float64(s.TruePositives + s.FalseNegatives)
+}
+
+func (s SegmentationScore) F1() float64 {
+  p, r := s.Precision(), s.Recall()
+  if p + r == 0 {
+    return 0
+  }
+
+  return 2 * p * r / (p + r)
+}
+
+func (s SegmentationScore) String() string {
+  return fmt.Sprintf("precision %.4f, recall %.4f, F1 %.4f", s.Precision(), s.Recall(), s.F1())
+}
+
+// Splits the gold sentences, joined by spaces, with the parameters and
+// compares the boundaries found with the real ones. Blank sentences are
+// skipped.
+func ScoreSegmentation(p ParameterSet, gold []string) SegmentationScore {
+  sentences := make([]string, 0, len(gold))
+  for _, v := range gold {
+    if v = strings.TrimSpace(v); v != "" {
+      sentences = append(sentences, v)
+    }
+  }
+
+  text := strings.Join(sentences, " ")
+  expected := sentenceEnds(text, sentences)
+
+  var tokenizer Tokenizer
+  tokenizer.SetParameterSet(p)
+  found := sentenceEnds(text, tokenizer.SentencesFromText(text))
+
+  var s SegmentationScore
+  for end := range found {
+    if expected[end] {
+      s.TruePositives++
+    } else {
+      s.FalsePositives++
+    }
+  }
+
+  s.FalseNegatives = len(expected) - s.TruePositives
+  return s
+}
+
+// The offsets in text where the sentences end, except at the end of text
+func sentenceEnds(text string, sentences []string) map[int]bool {
+  ends := map[int]bool{}
+  offset := 0
+
+  for _, v := range sentences {
+    v = strings.TrimSpace(v)
+    i := strings.Index(text[offset:], v)
+
+    if v == "" || i < 0 {
+      continue
+    }
+
+    offset += i + len(v)
+    if offset < len(text) {
+      ends[offset] = true
+    }
+  }
+
+  return ends
+}
+
+// The values to try for each setting. An empty list keeps the value of the
+// base settings.
+type TuningSpace struct {
+  AbbrevCutoffs []float64
+  AbbrevBackoffs []int
+  CollocationCutoffs []float64
+  SentStarterCutoffs []float64
+}
+
+type TuningOptions struct {
+  Space TuningSpace
+
+  // The settings that are not tuned, such as the measures.
+  // DefaultTrainerSettings if nil.
+  Base *TrainerSettings
+
+  // With Samples set, a random search tries that many of the combinations
+  // in the space, picked with Seed. Otherwise every combination is tried.
+  Samples int
+  Seed int64
+
+  // Seeds for each trainer, as Trainer.Seeds
+  Seeds *Overlay
+
+  // Called after each combination is scored
+  Progress func(done, total int, result TuningResult)
+}
+
+type TuningResult struct {
+  Settings TrainerSettings
+  Score SegmentationScore
+}
+
+// The results of a search, best first. Ties keep the order they were
+// tried in.
+type TuningReport struct {
+  Results []TuningResult
+
+  // trained with the best settings
+  Parameters *LanguageParameters
+}
+
+func (r TuningReport) Best() TuningResult {
+  if len(r.Results) == 0 {
+    return TuningResult{}
+  }
+
+  return r.Results[0]
+}
+
+var tuningColumns = []string{"abbrev_cutoff", "abbrev_backoff", "collocation_cutoff", "sent_starter_cutoff", "precision", "recall", "f1", "true_positives", "false_positives", "false_negatives"}
+
+// Writes the results as CSV with a header row, best first
+func (r TuningReport) WriteCSV(w io.Writer) error {
+  out := csv.NewWriter(w)
+  out.Write(tuningColumns)
+
+  for _, v := range r.Results {
+    out.Write([]string{
+      strconv.FormatFloat(v.Settings.AbbrevCutoff, 'g', -1, 64),
+      strconv.Itoa(v.Settings.AbbrevBackoff),
+      strconv.FormatFloat(v.Settings.CollocationCutoff, 'g', -1, 64),
+      strconv.FormatFloat(v.Settings.SentStarterCutoff, 'g', -1, 64),
+      strconv.FormatFloat(v.Score.Precision(), 'f', 4, 64),
+      strconv.FormatFloat(v.Score.Recall(), 'f', 4, 64),
+      strconv.FormatFloat(v.Score.F1(), 'f', 4, 64),
+      strconv.Itoa(v.Score.TruePositives),
+      strconv.Itoa(v.Score.FalsePositives),
+      strconv.Itoa(v.Score.FalseNegatives),
+    })
+  }
+
+  out.Flush()
+  return out.Error()
+}
+
+func (r TuningReport) String() string {
+  if len(r.Results) == 0 {
+    return "no settings tried"
+  }
+
+  best := r.Best()
+  return fmt.Sprintf("best of %d: abbrev cutoff %g, abbrev backoff %d, collocation cutoff %g, sentence starter cutoff %g (%s)",
+    len(r.Results), best.Settings.AbbrevCutoff, best.Settings.AbbrevBackoff,
+    best.Settings.CollocationCutoff, best.Settings.SentStarterCutoff, best.Score)
+}
+
+// Trains a model on the raw corpus for each combination of settings and
+// scores it against the gold sentences (see ScoreSegmentation)
+func Tune(corpus string, gold []string, options TuningOptions) (TuningReport, error) {
+  return TuneContext(context.Background(), corpus, gold, options)
+}
+
+// Like Tune, but stops with a *TrainingCanceledError when ctx is done
+func TuneContext(ctx context.Context, corpus string, gold []string, options TuningOptions) (TuningReport, error) {
+  var report TuningReport
+
+  if len(strings.TrimSpace(strings.Join(gold, ""))) == 0 {
+    return report, errors.New("punkt: no gold sentences to tune against")
+  }
+
+  words := SplitTextIntoMarkedWords(corpus)
+  candidates := options.candidates()
+  bestF1 := 0.0
+
+  for i, settings := range candidates {
+    trainer := &Trainer{Seeds: options.Seeds}
+    if err := trainer.SetSettings(settings); err != nil {
+      return report, err
+    }
+
+    p, err := trainer.TrainWithTokenizedTextContext(ctx, words, nil)
+    if err != nil {
+      return report, err
+    }
+
+    result := TuningResult{trainer.Settings(), ScoreSegmentation(p, gold)}
+    report.Results = append(report.Results, result)
+
+    // only the best model is kept, the first one on a tie
+    if report.Parameters == nil || result.Score.F1() > bestF1 {
+      report.Parameters, bestF1 = p, result.Score.F1()
+    }
+
+    if options.Progress != nil {
+      options.Progress(i + 1, len(candidates), result)
+    }
+  }
+
+  sort.SliceStable(report.Results, func(i, j int) bool {
+    return report.Results[i].Score.F1() > report.Results[j].Score.F1()
+  })
+
+  return report, nil
+}
+
+// The settings to try, in order
+func (o TuningOptions) candidates() []TrainerSettings {
+  base := DefaultTrainerSettings()
+  if o.Base != nil {
+    base = *o.Base
+  }
+
+  abbrevCutoffs := o.Space.AbbrevCutoffs
+  if len(abbrevCutoffs) == 0 {
+    abbrevCutoffs = []float64{base.AbbrevCutoff}
+  }
+
+  abbrevBackoffs := o.Space.AbbrevBackoffs
+  if len(abbrevBackoffs) == 0 {
+    abbrevBackoffs = []int{base.AbbrevBackoff}
+  }
+
+  collocationCutoffs := o.Space.CollocationCutoffs
+  if len(collocationCutoffs) == 0 {
+    collocationCutoffs = []float64{base.CollocationCutoff}
+  }
+
+  starterCutoffs := o.Space.SentStarterCutoffs
+  if len(starterCutoffs) == 0 {
+    starterCutoffs = []float64{base.SentStarterCutoff}
+  }
+
+  var grid []TrainerSettings
+  for _, a := range abbrevCutoffs {
+    for _, b := range abbrevBackoffs {
+      for _, c := range collocationCutoffs {
+        for _, s := range starterCutoffs {
+          settings := base
+          settings.AbbrevCutoff, settings.AbbrevBackoff = a, b
+          settings.CollocationCutoff, settings.SentStarterCutoff = c, s
+          grid = append(grid, settings)
+        }
+      }
+    }
+  }
+
+  if o.Samples <= 0 || o.Samples >= len(grid) {
+    return grid
+  }
+
+  picked := rand.New(rand.NewSource(o.Seed)).Perm(len(grid))[:o.Samples]
+  out := make([]TrainerSettings, len(picked))
+  for i, v := range picked {
+    out[i] = grid[v]
+  }
+
+  return out
+}
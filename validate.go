@@ -0,0 +1,497 @@
+package punkt
+
+import (
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "io"
+  "strings"
+)
+
+// One problem found in a model. Field names the JSON key, with the index or
+// map key of the offending entry, eg collocations[12] or ortho_context["dog"].
+// Offset is the byte offset in the JSON input, or -1 when the problem was
+// found in loaded parameters.
+type ValidationError struct {
+  Field string
+  Offset int64
+  Message string
+}
+
+func (e ValidationError) Error() string {
+  if e.Offset < 0 {
+    return fmt.Sprintf("%s: %s", e.Field, e.Message)
+  }
+
+  return fmt.Sprintf("%s (offset %d): %s", e.Field, e.Offset, e.Message)
+}
+
+// Every problem found in a model, in the order they were found
+type ValidationErrors []ValidationError
+
+func (v ValidationErrors) Error() string {
+  lines := make([]string, len(v))
+  for i, e := range v {
+    lines[i] = e.Error()
+  }
+
+  return fmt.Sprintf("punkt: %d problems in model:\n  %s", len(v), strings.Join(lines, "\n  "))
+}
+
+const orthoKnownFlags = ORTHO_UC | ORTHO_LC
+
+var jsonParameterKeys = []string{"sentence_starters", "abbrev_types", "collocations", "ortho_context", "metadata"}
+
+// Checks that the parameters could have come from training. Returns nil or
+// ValidationErrors.
+func (p LanguageParameters) Validate() error {
+  var errs ValidationErrors
+  add := func(field, format string, args ...interface{}) {
+    errs = append(errs, ValidationError{Field: field, Offset: -1, Message: fmt.Sprintf(format, args...)})
+  }
+
+  checkTypes := func(name string, set map[string]bool) {
+    for _, k := range sortedKeys(set) {
+      if msg := checkType(k); msg != "" {
+        add(fmt.Sprintf("%s[%q]", name, k), "%s", msg)
+      }
+    }
+  }
+
+  checkTypes("abbrev_types", p.AbbrevTypes)
+  checkTypes("sentence_starters", p.SentenceStarters)
+
+  for _, k := range sortedCollocations(p.Collocations) {
+    if msg := checkCollocation(k); msg != "" {
+      add(fmt.Sprintf("collocations[%q]", k.String()), "%s", msg)
+    }
+  }
+
+  types := make(map[string]bool, len(p.OrthographicContext))
+  for k := range p.OrthographicContext {
+    types[k] = true
+  }
+
+  for _, k := range sortedKeys(types) {
+    field := fmt.Sprintf("ortho_context[%q]", k)
+
+    if msg := checkType(k); msg != "" {
+      add(field, "%s", msg)
+    }
+
+    if msg := checkOrthoFlags(int64(p.OrthographicContext[k])); msg != "" {
+      add(field, "%s", msg)
+    }
+  }
+
+  if len(errs) == 0 {
+    return nil
+  }
+
+  return errs
+}
+
+func checkType(s string) string {
+  if len(s) == 0 {
+    return "empty type"
+  }
+
+  return ""
+}
+
+func checkCollocation(c Collocation) string {
+  if len(c.Type1) == 0 || len(c.Type2) == 0 {
+    return fmt.Sprintf("collocation %q has an empty type", c.String())
+  }
+
+  return ""
+}
+
+func checkOrthoFlags(v int64) string {
+  switch {
+  case v < 0:
+    return fmt.Sprintf("negative flags %d", v)
+  case v == 0:
+    return "no flags set"
+  case v &^ int64(orthoKnownFlags) != 0:
+    return fmt.Sprintf("unknown flag bits 0x%x", v &^ int64(orthoKnownFlags))
+  }
+
+  return ""
+}
+
+// Checks a JSON model without loading it. Unlike LoadParametersFromJSONString
+// this reports unknown or misspelled keys, malformed collocations, bad ortho
+// flags and duplicate entries. Returns nil or ValidationErrors.
+func ValidateJSON(contents []byte) error {
+  v := jsonValidator{dec: json.NewDecoder(bytes.NewReader(contents))}
+  v.dec.UseNumber()
+  v.run()
+
+  if len(v.errs) == 0 {
+    return nil
+  }
+
+  return v.errs
+}
+
+// Validates the JSON with ValidateJSON before loading it
+func LoadParametersFromJSONStrict(contents []byte) (*LanguageParameters, error) {
+  if err := ValidateJSON(contents); err != nil {
+    return nil, err
+  }
+
+  return LoadParametersFromJSONString(contents), nil
+}
+
+type jsonValidator struct {
+  dec *json.Decoder
+  errs ValidationErrors
+}
+
+func (v *jsonValidator) add(field string, offset int64, format string, args ...interface{}) {
+  v.errs = append(v.errs, ValidationError{Field: field, Offset: offset, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *jsonValidator) run() {
+  tok, err := v.dec.Token()
+  if err != nil || tok != json.Delim('{') {
+    v.add("(root)", 0, "model must be a JSON object")
+    return
+  }
+
+  seen := map[string]bool{}
+
+  for v.dec.More() {
+    offset := v.dec.InputOffset()
+    tok, err := v.dec.Token()
+    if err != nil {
+      v.add("(root)", offset, "%v", err)
+      return
+    }
+
+    key := tok.(string)
+    if seen[key] {
+      v.add(key, offset, "duplicate key")
+    }
+    seen[key] = true
+
+    var ok bool
+    switch key {
+    case "sentence_starters", "abbrev_types":
+      ok = v.stringList(key, checkType)
+    case "collocations":
+      ok = v.collocations()
+    case "ortho_context":
+      ok = v.orthoContext()
+    case "metadata":
+      ok = v.metadata()
+    default:
+      msg := fmt.Sprintf("unknown key %q", key)
+      if guess := closestString(key, jsonParameterKeys); guess != "" {
+        msg += fmt.Sprintf(", did you mean %q?", guess)
+      }
+      v.add(key, offset, "%s", msg)
+      ok = v.skip()
+    }
+
+    if !ok {
+      return
+    }
+  }
+}
+
+// skips over the next value, returning false if the JSON is broken
+func (v *jsonValidator) skip() bool {
+  var raw json.RawMessage
+  if err := v.dec.Decode(&raw); err != nil {
+    v.add("(root)", v.dec.InputOffset(), "%v", err)
+    return false
+  }
+
+  return true
+}
+
+// reads the opening delimiter of a list or object; null is treated as empty
+func (v *jsonValidator) open(field string, delim json.Delim) (found, ok bool) {
+  offset := v.dec.InputOffset()
+  tok, err := v.dec.Token()
+  if err != nil {
+    v.add(field, offset, "%v", err)
+    return false, false
+  }
+
+  if tok == nil {
+    return false, true
+  }
+
+  if tok != delim {
+    v.add(field, offset, "expected %v, got %v", delim, tok)
+
+    if d, isDelim := tok.(json.Delim); isDelim && (d == '[' || d == '{') {
+      return false, v.skipRest()
+    }
+
+    return false, true
+  }
+
+  return true, true
+}
+
+// skips to the end of a list or object whose opening delimiter was already read
+func (v *jsonValidator) skipRest() bool {
+  for depth := 1; depth > 0; {
+    tok, err := v.dec.Token()
+    if err != nil {
+      v.add("(root)", v.dec.InputOffset(), "%v", err)
+      return false
+    }
+
+    if d, isDelim := tok.(json.Delim); isDelim {
+      if d == '[' || d == '{' {
+        depth++
+      } else {
+        depth--
+      }
+    }
+  }
+
+  return true
+}
+
+func (v *jsonValidator) stringList(key string, check func(string) string) bool {
+  found, ok := v.open(key, '[')
+  if !found {
+    return ok
+  }
+
+  seen := map[string]int{}
+
+  for i := 0; v.dec.More(); i++ {
+    field := fmt.Sprintf("%s[%d]", key, i)
+    offset := v.dec.InputOffset()
+    tok, err := v.dec.Token()
+    if err != nil {
+      v.add(field, offset, "%v", err)
+      return false
+    }
+
+    s, isString := tok.(string)
+    if !isString {
+      v.add(field, offset, "expected a string, got %v", tok)
+      if d, isDelim := tok.(json.Delim); isDelim && !v.skipRestOf(d) {
+        return false
+      }
+      continue
+    }
+
+    if first, dup := seen[s]; dup {
+      v.add(field, offset, "duplicate of %s[%d] %q", key, first, s)
+    } else {
+      seen[s] = i
+    }
+
+    if msg := check(s); msg != "" {
+      v.add(field, offset, "%s", msg)
+    }
+  }
+
+  _, err := v.dec.Token()
+  return err == nil
+}
+
+func (v *jsonValidator) skipRestOf(d json.Delim) bool {
+  if d == '[' || d == '{' {
+    return v.skipRest()
+  }
+
+  return true
+}
+
+// collocations may be "type1|type2" strings or [type1, type2] pairs as in
+// the files in data/
+func (v *jsonValidator) collocations() bool {
+  found, ok := v.open("collocations", '[')
+  if !found {
+    return ok
+  }
+
+  seen := map[Collocation]int{}
+
+  for i := 0; v.dec.More(); i++ {
+    field := fmt.Sprintf("collocations[%d]", i)
+    offset := v.dec.InputOffset()
+
+    var raw json.RawMessage
+    if err := v.dec.Decode(&raw); err != nil {
+      v.add(field, offset, "%v", err)
+      return false
+    }
+
+    var str string
+    var pair []string
+    var key Collocation
+
+    if err := json.Unmarshal(raw, &str); err == nil {
+      key, err = ParseCollocation(str)
+      if err != nil {
+        v.add(field, offset, "%v", err)
+        continue
+      }
+    } else if err := json.Unmarshal(raw, &pair); err == nil {
+      if len(pair) != 2 {
+        v.add(field, offset, "collocation pair has %d types", len(pair))
+        continue
+      }
+
+      key = Collocation{pair[0], pair[1]}
+    } else {
+      v.add(field, offset, "collocation must be a \"type1|type2\" string or a pair of strings, got %s", raw)
+      continue
+    }
+
+    if msg := checkCollocation(key); msg != "" {
+      v.add(field, offset, "%s", msg)
+      continue
+    }
+
+    if first, dup := seen[key]; dup {
+      v.add(field, offset, "duplicate of collocations[%d] %q", first, key.String())
+    } else {
+      seen[key] = i
+    }
+  }
+
+  _, err := v.dec.Token()
+  return err == nil
+}
+
+func (v *jsonValidator) orthoContext() bool {
+  found, ok := v.open("ortho_context", '{')
+  if !found {
+    return ok
+  }
+
+  seen := map[string]bool{}
+
+  for v.dec.More() {
+    offset := v.dec.InputOffset()
+    tok, err := v.dec.Token()
+    if err != nil {
+      v.add("ortho_context", offset, "%v", err)
+      return false
+    }
+
+    key := tok.(string)
+    field := fmt.Sprintf("ortho_context[%q]", key)
+
+    if seen[key] {
+      v.add(field, offset, "duplicate key")
+    }
+    seen[key] = true
+
+    if msg := checkType(key); msg != "" {
+      v.add(field, offset, "%s", msg)
+    }
+
+    offset = v.dec.InputOffset()
+    tok, err = v.dec.Token()
+    if err != nil {
+      v.add(field, offset, "%v", err)
+      return false
+    }
+
+    n, isNumber := tok.(json.Number)
+    if !isNumber {
+      v.add(field, offset, "flags must be a number, got %v", tok)
+      if d, isDelim := tok.(json.Delim); isDelim && !v.skipRestOf(d) {
+        return false
+      }
+      continue
+    }
+
+    flags, err := n.Int64()
+    if err != nil {
+      v.add(field, offset, "flags must be an integer, got %v", n)
+      continue
+    }
+
+    if msg := checkOrthoFlags(flags); msg != "" {
+      v.add(field, offset, "%s", msg)
+    }
+  }
+
+  _, err := v.dec.Token()
+  return err == nil
+}
+
+func (v *jsonValidator) metadata() bool {
+  offset := v.dec.InputOffset()
+
+  var raw json.RawMessage
+  if err := v.dec.Decode(&raw); err != nil {
+    v.add("metadata", offset, "%v", err)
+    return false
+  }
+
+  dec := json.NewDecoder(bytes.NewReader(raw))
+  dec.DisallowUnknownFields()
+
+  var m ModelMetadata
+  if err := dec.Decode(&m); err != nil && err != io.EOF {
+    v.add("metadata", offset, "%v", err)
+  }
+
+  return true
+}
+
+// The candidate within a small edit distance of s, or "" if there is none
+func closestString(s string, candidates []string) string {
+  best := ""
+  bestDistance := len(s)/2 + 1
+
+  for _, c := range candidates {
+    if d := editDistance(strings.ToLower(s), c); d < bestDistance {
+      best = c
+      bestDistance = d
+    }
+  }
+
+  return best
+}
+
+// Levenshtein distance between two strings, counted in runes
+func editDistance(a, b string) int {
+  ra, rb := []rune(a), []rune(b)
+  prev := make([]int, len(rb)+1)
+  cur := make([]int, len(rb)+1)
+
+  for j := range prev {
+    prev[j] = j
+  }
+
+  for i := 1; i <= len(ra); i++ {
+    cur[0] = i
+
+    for j := 1; j <= len(rb); j++ {
+      cost := 1
+      if ra[i-1] == rb[j-1] {
+        cost = 0
+      }
+
+      cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+    }
+
+    prev, cur = cur, prev
+  }
+
+  return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+  if a < b {
+    return a
+  }
+
+  return b
+}
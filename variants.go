@@ -0,0 +1,252 @@
+package punkt
+
+import (
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "io/ioutil"
+  "sync"
+)
+
+// A regional model that is derived from a base language, eg Austrian German
+// from german. Only the changes to the base are kept, and they are applied
+// whenever the variant is loaded, so the variant follows updates to its base.
+type Variant struct {
+  Name string
+  Base string // a bundled language or another registered variant
+  Tags []string // language tags that resolve to the variant, eg "de-CH"
+  Changes ParametersDiff
+}
+
+var (
+  variantsLock sync.RWMutex
+  variants = map[string]Variant{}
+)
+
+// Builds a variant from complete parameters by comparing them with its base
+func DeriveVariant(name, base string, p *LanguageParameters) (Variant, error) {
+  baseParameters, err := loadLanguage(base)
+  if err != nil {
+    return Variant{}, err
+  }
+
+  return Variant{Name: name, Base: base, Changes: baseParameters.Diff(p)}, nil
+}
+
+// Loads the base and applies the changes
+func (v Variant) Resolve() (*LanguageParameters, error) {
+  base, err := loadLanguage(v.Base)
+  if err != nil {
+    return nil, err
+  }
+
+  return v.Changes.Apply(base), nil
+}
+
+func lookupVariant(name string) (Variant, bool) {
+  variantsLock.RLock()
+  defer variantsLock.RUnlock()
+
+  v, found := variants[name]
+  return v, found
+}
+
+func variantNames() []string {
+  variantsLock.RLock()
+  defer variantsLock.RUnlock()
+
+  names := make(map[string]bool, len(variants))
+  for k := range variants {
+    names[k] = true
+  }
+
+  return sortedKeys(names)
+}
+
+// Makes the variant available to LoadLanguage and SetLanguage, under its name
+// and its tags. Registering a variant again replaces it.
+func RegisterVariant(v Variant) error {
+  if v.Name == "" {
+    return fmt.Errorf("punkt: variant has no name")
+  }
+
+  if v.Name != normalizeLanguageTag(v.Name) {
+    return fmt.Errorf("punkt: variant name %q must be lower case, without spaces or _", v.Name)
+  }
+
+  if isBundledLanguage(v.Name) {
+    return fmt.Errorf("punkt: variant %q has the name of a bundled language", v.Name)
+  }
+
+  for _, tag := range v.Tags {
+    if normalizeLanguageTag(tag) == "" {
+      return fmt.Errorf("punkt: variant %q has an empty tag", v.Name)
+    }
+  }
+
+  if err := registerVariant(v); err != nil {
+    if unknown, ok := err.(*UnknownLanguageError); ok {
+      unknown.Closest = closestLanguages(unknown.Tag)
+    }
+
+    return err
+  }
+
+  for _, tag := range v.Tags {
+    if err := RegisterLanguageAlias(tag, v.Name); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func registerVariant(v Variant) error {
+  variantsLock.Lock()
+  defer variantsLock.Unlock()
+
+  // follow the bases down to a bundled language, so variants never form a loop
+  for base := v.Base; !isBundledLanguage(base); {
+    if base == v.Name {
+      return fmt.Errorf("punkt: variant %q is based on itself", v.Name)
+    }
+
+    next, found := variants[base]
+    if !found {
+      return &UnknownLanguageError{Tag: base}
+    }
+
+    base = next.Base
+  }
+
+  variants[v.Name] = v
+  return nil
+}
+
+// Removes a registered variant and the aliases of its tags. Variants based
+// on it can no longer be loaded until it is registered again.
+func UnregisterVariant(name string) {
+  variantsLock.Lock()
+  v, found := variants[name]
+  delete(variants, name)
+  variantsLock.Unlock()
+
+  if !found {
+    return
+  }
+
+  languageAliasesLock.Lock()
+  defer languageAliasesLock.Unlock()
+
+  // unless a tag was aliased to something else since
+  for _, tag := range v.Tags {
+    if tag = normalizeLanguageTag(tag); languageAliases[tag] == name {
+      delete(languageAliases, tag)
+    }
+  }
+}
+
+// The delta file format. Collocations use the same "type1|type2" strings as
+// model files, and ortho_context holds the new flags of changed types, where
+// 0 removes the entry.
+type jsonVariant struct {
+  Name string `json:"name"`
+  Base string `json:"base"`
+  Tags []string `json:"tags,omitempty"`
+  AbbrevTypesAdded []string `json:"abbrev_types_added,omitempty"`
+  AbbrevTypesRemoved []string `json:"abbrev_types_removed,omitempty"`
+  CollocationsAdded JsonCollocations `json:"collocations_added,omitempty"`
+  CollocationsRemoved JsonCollocations `json:"collocations_removed,omitempty"`
+  SentenceStartersAdded []string `json:"sentence_starters_added,omitempty"`
+  SentenceStartersRemoved []string `json:"sentence_starters_removed,omitempty"`
+  OrthoContext map[string]OrthoContext `json:"ortho_context,omitempty"`
+}
+
+// Serializes the variant as a delta file
+func (v Variant) ToJSON() ([]byte, error) {
+  d := v.Changes
+  m := jsonVariant{
+    Name: v.Name,
+    Base: v.Base,
+    Tags: v.Tags,
+    AbbrevTypesAdded: d.AbbrevTypesAdded,
+    AbbrevTypesRemoved: d.AbbrevTypesRemoved,
+    CollocationsAdded: JsonCollocations(d.CollocationsAdded),
+    CollocationsRemoved: JsonCollocations(d.CollocationsRemoved),
+    SentenceStartersAdded: d.SentenceStartersAdded,
+    SentenceStartersRemoved: d.SentenceStartersRemoved,
+  }
+
+  if len(d.OrthoChanged) > 0 {
+    m.OrthoContext = make(map[string]OrthoContext, len(d.OrthoChanged))
+    for _, c := range d.OrthoChanged {
+      m.OrthoContext[c.Type] = c.New
+    }
+  }
+
+  return json.Marshal(m)
+}
+
+// Reads a delta file. The old flags of changed ortho context entries are not
+// stored, so they are left at 0 in Changes.OrthoChanged.
+func LoadVariantFromJSON(contents []byte) (Variant, error) {
+  var m jsonVariant
+
+  dec := json.NewDecoder(bytes.NewReader(contents))
+  dec.DisallowUnknownFields()
+  if err := dec.Decode(&m); err != nil {
+    return Variant{}, fmt.Errorf("punkt: bad variant: %v", err)
+  }
+
+  if m.Name == "" || m.Base == "" {
+    return Variant{}, fmt.Errorf("punkt: bad variant: name and base are required")
+  }
+
+  v := Variant{
+    Name: m.Name,
+    Base: m.Base,
+    Tags: m.Tags,
+    Changes: ParametersDiff{
+      AbbrevTypesAdded: m.AbbrevTypesAdded,
+      AbbrevTypesRemoved: m.AbbrevTypesRemoved,
+      CollocationsAdded: []Collocation(m.CollocationsAdded),
+      CollocationsRemoved: []Collocation(m.CollocationsRemoved),
+      SentenceStartersAdded: m.SentenceStartersAdded,
+      SentenceStartersRemoved: m.SentenceStartersRemoved,
+    },
+  }
+
+  types := make(map[string]bool, len(m.OrthoContext))
+  for k := range m.OrthoContext {
+    types[k] = true
+  }
+
+  for _, k := range sortedKeys(types) {
+    v.Changes.OrthoChanged = append(v.Changes.OrthoChanged, OrthoChange{Type: k, New: m.OrthoContext[k]})
+  }
+
+  return v, nil
+}
+
+func LoadVariantFromFile(path string) (Variant, error) {
+  contents, err := ioutil.ReadFile(path)
+  if err != nil {
+    return Variant{}, err
+  }
+
+  v, err := LoadVariantFromJSON(contents)
+  if err != nil {
+    return Variant{}, fmt.Errorf("%s: %v", path, err)
+  }
+
+  return v, nil
+}
+
+func SaveVariantToJSON(v Variant, path string) error {
+  contents, err := v.ToJSON()
+  if err != nil {
+    return err
+  }
+
+  return ioutil.WriteFile(path, contents, 0644)
+}